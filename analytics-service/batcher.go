@@ -0,0 +1,241 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Farhang-Osman/url-shortener-project/common/eventbus"
+)
+
+const (
+	batchRetryMaxBackoff = 30 * time.Second
+	maxStoreAttempts     = 5
+)
+
+// batchConfig controls how many messages are written per transaction
+type batchConfig struct {
+	Size          int           `yaml:"size" env:"BATCH_SIZE" flag:"batch-size" default:"500" usage:"maximum messages stored per transaction"`
+	FlushInterval time.Duration `yaml:"flush_interval" env:"BATCH_FLUSH_INTERVAL" flag:"batch-flush-interval" default:"200ms" usage:"how long to wait for a batch to fill up"`
+}
+
+func (c *batchConfig) Validate() error {
+	if c.Size <= 0 {
+		return fmt.Errorf("batch size must be positive, got %d", c.Size)
+	}
+	if c.FlushInterval <= 0 {
+		return fmt.Errorf("batch flush interval must be positive, got %v", c.FlushInterval)
+	}
+	return nil
+}
+
+// decodedMessage pairs an event with the message it came from
+type decodedMessage[T any] struct {
+	msg   eventbus.Message
+	event T
+}
+
+// batchConsumer reads messages into batches of up to cfg.Size, or whatever
+// arrived within cfg.FlushInterval of the first message, decodes them and
+// stores each batch in one call. Offsets are committed only after every
+// message has either been stored or handed to the dead-letter queue.
+//
+// Transient store errors are retried a bounded number of times. If a batch
+// fails with a permanent error it is split up to find the bad messages, so
+// one poison message doesn't hold back the rest.
+type batchConsumer[T any] struct {
+	name   string
+	sub    eventbus.Subscriber
+	dlq    *deadLetterQueue
+	cfg    batchConfig
+	decode func(msg eventbus.Message) (T, error)
+	store  func(ctx context.Context, events []T) error
+}
+
+// run consumes until ctx is cancelled. The batch in flight at that point is
+// still stored and committed using storeCtx, which the caller cancels only
+// if the shutdown grace period runs out.
+func (c *batchConsumer[T]) run(ctx, storeCtx context.Context) {
+	batch := make([]eventbus.Message, 0, c.cfg.Size)
+	for {
+		batch = batch[:0]
+
+		// Block until there is something to do
+		msg, err := c.sub.Fetch(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("Error reading %s message: %v", c.name, err)
+			// Wait before retrying, but not past shutdown
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(5 * time.Second):
+			}
+			continue
+		}
+		batch = append(batch, msg)
+
+		// Then fill the batch until it is full or the flush interval passes
+		deadline := time.Now().Add(c.cfg.FlushInterval)
+		for len(batch) < c.cfg.Size {
+			fetchCtx, cancel := context.WithDeadline(ctx, deadline)
+			msg, err := c.sub.Fetch(fetchCtx)
+			cancel()
+			if err != nil {
+				if !errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+					log.Printf("Error reading %s message: %v", c.name, err)
+				}
+				break
+			}
+			batch = append(batch, msg)
+		}
+
+		if !c.process(storeCtx, batch) {
+			return
+		}
+		if ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+// process stores a batch, dead-letters whatever could not be stored and then
+// commits the batch's offsets. It returns false if ctx was cancelled first.
+func (c *batchConsumer[T]) process(ctx context.Context, batch []eventbus.Message) bool {
+	var dead []deadLetter
+	decoded := make([]decodedMessage[T], 0, len(batch))
+	for _, msg := range batch {
+		event, err := c.decode(msg)
+		if err != nil {
+			log.Printf("Error decoding %s message at offset %d: %v", c.name, msg.Offset, err)
+			dead = append(dead, deadLetter{msg: msg, reason: reasonUndecodable, err: err})
+			continue
+		}
+		decoded = append(decoded, decodedMessage[T]{msg: msg, event: event})
+	}
+
+	dead = append(dead, c.storeAll(ctx, decoded)...)
+	if ctx.Err() != nil {
+		return false
+	}
+
+	if len(dead) > 0 {
+		if !retryWithBackoff(ctx, func() error { return c.dlq.publish(ctx, dead) }, func(err error, wait time.Duration) {
+			log.Printf("Error publishing %d %s messages to dead-letter queue, retrying in %v: %v", len(dead), c.name, wait, err)
+		}) {
+			return false
+		}
+		log.Printf("Sent %d %s messages to dead-letter queue", len(dead), c.name)
+	}
+
+	if !retryWithBackoff(ctx, func() error { return c.sub.Commit(ctx, batch...) }, func(err error, wait time.Duration) {
+		log.Printf("Error committing batch of %d %s events, retrying in %v: %v", len(batch), c.name, wait, err)
+	}) {
+		return false
+	}
+
+	log.Printf("Stored %d %s events", len(decoded), c.name)
+	return true
+}
+
+// storeAll stores the decoded messages and returns the ones that must be
+// dead-lettered
+func (c *batchConsumer[T]) storeAll(ctx context.Context, decoded []decodedMessage[T]) []deadLetter {
+	if len(decoded) == 0 {
+		return nil
+	}
+
+	events := make([]T, len(decoded))
+	for i, d := range decoded {
+		events[i] = d.event
+	}
+
+	err := c.storeWithRetry(ctx, events)
+	if err == nil || ctx.Err() != nil {
+		return nil
+	}
+
+	// Still failing after retries: the database is unhealthy rather than
+	// any one message being bad, so park the whole batch for replay
+	if isTransient(err) || len(decoded) == 1 {
+		reason := reasonPermanentFailure
+		if isTransient(err) {
+			reason = reasonRetriesExhausted
+		}
+		log.Printf("Giving up on batch of %d %s events: %v", len(decoded), c.name, err)
+		dead := make([]deadLetter, len(decoded))
+		for i, d := range decoded {
+			dead[i] = deadLetter{msg: d.msg, reason: reason, err: err, attempts: maxStoreAttempts}
+		}
+		return dead
+	}
+
+	// A permanent error: store messages one by one to isolate the bad ones
+	log.Printf("Batch of %d %s events failed, storing individually: %v", len(decoded), c.name, err)
+	var dead []deadLetter
+	for _, d := range decoded {
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err := c.storeWithRetry(ctx, []T{d.event}); err != nil {
+			reason := reasonPermanentFailure
+			if isTransient(err) {
+				reason = reasonRetriesExhausted
+			}
+			dead = append(dead, deadLetter{msg: d.msg, reason: reason, err: err, attempts: maxStoreAttempts})
+		}
+	}
+	return dead
+}
+
+// storeWithRetry retries transient store errors up to maxStoreAttempts times
+func (c *batchConsumer[T]) storeWithRetry(ctx context.Context, events []T) error {
+	wait := time.Second
+	var err error
+	for attempt := 1; attempt <= maxStoreAttempts; attempt++ {
+		err = c.store(ctx, events)
+		if err == nil || !isTransient(err) || ctx.Err() != nil {
+			return err
+		}
+		if attempt == maxStoreAttempts {
+			break
+		}
+
+		log.Printf("Transient error storing %d %s events (attempt %d/%d), retrying in %v: %v",
+			len(events), c.name, attempt, maxStoreAttempts, wait, err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+		wait = min(wait*2, batchRetryMaxBackoff)
+	}
+	return err
+}
+
+// retryWithBackoff calls fn until it succeeds, doubling the wait between
+// attempts. It returns false if ctx is cancelled first.
+func retryWithBackoff(ctx context.Context, fn func() error, onError func(err error, wait time.Duration)) bool {
+	wait := time.Second
+	for {
+		err := fn()
+		if err == nil {
+			return true
+		}
+		if ctx.Err() != nil {
+			return false
+		}
+
+		onError(err, wait)
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(wait):
+		}
+		wait = min(wait*2, batchRetryMaxBackoff)
+	}
+}
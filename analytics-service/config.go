@@ -0,0 +1,12 @@
+package main
+
+import "github.com/Farhang-Osman/url-shortener-project/common/config"
+
+// Config is loaded from the environment, flags and an optional YAML file;
+// see package config
+type Config struct {
+	ListenAddr string          `yaml:"listen_addr" env:"LISTEN_ADDR" flag:"listen" default:":50053" usage:"gRPC listen address"`
+	Database   config.Database `yaml:"database"`
+	Kafka      config.Kafka    `yaml:"kafka"`
+	Batch      batchConfig     `yaml:"batch"`
+}
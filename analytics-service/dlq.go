@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/Farhang-Osman/url-shortener-project/common/eventbus"
+)
+
+const dlqSuffix = ".dlq"
+
+// Reasons recorded in the dlq-reason header
+const (
+	reasonUndecodable      = "undecodable"
+	reasonPermanentFailure = "permanent_failure"
+	reasonRetriesExhausted = "retries_exhausted"
+)
+
+// Headers added to dead-lettered messages. The original key, value and
+// headers are kept as they were.
+const (
+	headerDLQReason         = "dlq-reason"
+	headerDLQError          = "dlq-error"
+	headerDLQAttempts       = "dlq-attempts"
+	headerDLQFailedAt       = "dlq-failed-at"
+	headerDLQOriginalTopic  = "dlq-original-topic"
+	headerDLQOriginalPart   = "dlq-original-partition"
+	headerDLQOriginalOffset = "dlq-original-offset"
+)
+
+type deadLetter struct {
+	msg      eventbus.Message
+	reason   string
+	err      error
+	attempts int
+}
+
+// deadLetterQueue routes messages that can't be stored to "<topic>.dlq"
+type deadLetterQueue struct {
+	publisher eventbus.Publisher
+}
+
+func newDeadLetterQueue(publisher eventbus.Publisher) *deadLetterQueue {
+	return &deadLetterQueue{publisher: publisher}
+}
+
+func (q *deadLetterQueue) publish(ctx context.Context, dead []deadLetter) error {
+	msgs := make([]eventbus.Message, 0, len(dead))
+	for _, d := range dead {
+		headers := make(map[string]string, len(d.msg.Headers)+7)
+		for k, v := range d.msg.Headers {
+			headers[k] = v
+		}
+		headers[headerDLQReason] = d.reason
+		headers[headerDLQError] = d.err.Error()
+		headers[headerDLQAttempts] = strconv.Itoa(d.attempts)
+		headers[headerDLQFailedAt] = time.Now().UTC().Format(time.RFC3339)
+		headers[headerDLQOriginalTopic] = d.msg.Topic
+		headers[headerDLQOriginalPart] = strconv.Itoa(d.msg.Partition)
+		headers[headerDLQOriginalOffset] = strconv.FormatInt(d.msg.Offset, 10)
+
+		msgs = append(msgs, eventbus.Message{
+			Topic:   d.msg.Topic + dlqSuffix,
+			Key:     d.msg.Key,
+			Value:   d.msg.Value,
+			Headers: headers,
+		})
+	}
+	return q.publisher.Publish(ctx, msgs...)
+}
+
+// isTransient reports whether a store error is worth retrying: lost
+// connections, timeouts, deadlocks, serialization failures and the server
+// being short on resources or shutting down. Anything else (bad data,
+// constraint violations) will fail the same way again.
+func isTransient(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch {
+		case strings.HasPrefix(pgErr.Code, "08"), // connection_exception
+			strings.HasPrefix(pgErr.Code, "40"),  // transaction_rollback (deadlock, serialization)
+			strings.HasPrefix(pgErr.Code, "53"),  // insufficient_resources
+			strings.HasPrefix(pgErr.Code, "57P"): // operator_intervention (shutdown)
+			return true
+		default:
+			return false
+		}
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) || pgconn.SafeToRetry(err) || pgconn.Timeout(err) {
+		return true
+	}
+
+	// Errors from pgx itself before a query reached the server (e.g. the
+	// pool failing to connect) are not PgErrors and are worth retrying
+	var connectErr *pgconn.ConnectError
+	return errors.As(err, &connectErr)
+}
+
+// runReplayDLQ implements the "replay-dlq" subcommand. It moves messages from
+// "<topic>.dlq" back onto their original topic, stripping the dlq-* headers,
+// and stops once the queue has been idle for -idle.
+func runReplayDLQ(brokers, args []string) error {
+	fs := flag.NewFlagSet("replay-dlq", flag.ExitOnError)
+	topic := fs.String("topic", "", "main topic whose dead-letter queue to replay, e.g. "+clickTopic+" (required)")
+	maxMessages := fs.Int("max", 0, "stop after replaying this many messages (0 means no limit)")
+	idle := fs.Duration("idle", 10*time.Second, "stop when no message arrives for this long")
+	fs.Parse(args)
+
+	if *topic == "" {
+		return fmt.Errorf("-topic is required")
+	}
+	*topic = strings.TrimSuffix(*topic, dlqSuffix)
+
+	sub := eventbus.NewKafkaSubscriber(eventbus.KafkaSubscriberConfig{
+		Brokers: brokers,
+		Topic:   *topic + dlqSuffix,
+		GroupID: "analytics-dlq-replay",
+		MaxWait: time.Second,
+	})
+	defer sub.Close()
+
+	publisher := eventbus.NewKafkaPublisher(brokers...)
+	defer publisher.Close()
+
+	replayed := 0
+	for *maxMessages == 0 || replayed < *maxMessages {
+		fetchCtx, cancel := context.WithTimeout(context.Background(), *idle)
+		msg, err := sub.Fetch(fetchCtx)
+		cancel()
+		if err != nil {
+			if errors.Is(err, context.DeadlineExceeded) {
+				break
+			}
+			return err
+		}
+
+		headers := make(map[string]string, len(msg.Headers))
+		for k, v := range msg.Headers {
+			if !strings.HasPrefix(k, "dlq-") {
+				headers[k] = v
+			}
+		}
+
+		replay := eventbus.Message{Topic: *topic, Key: msg.Key, Value: msg.Value, Headers: headers}
+		if err := publisher.Publish(context.Background(), replay); err != nil {
+			return fmt.Errorf("failed to republish offset %d: %w", msg.Offset, err)
+		}
+		if err := sub.Commit(context.Background(), msg); err != nil {
+			return fmt.Errorf("failed to commit offset %d: %w", msg.Offset, err)
+		}
+		replayed++
+	}
+
+	log.Printf("Replayed %d messages from %s%s to %s", replayed, *topic, dlqSuffix, *topic)
+	return nil
+}
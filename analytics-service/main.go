@@ -2,147 +2,188 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"log"
+	"net"
+	"os/signal"
+	"sync"
+	"syscall"
 	"time"
 
-	"github.com/segmentio/kafka-go"
+	"google.golang.org/grpc"
 
+	"github.com/Farhang-Osman/url-shortener-project/common/config"
 	db "github.com/Farhang-Osman/url-shortener-project/common/db"
+	"github.com/Farhang-Osman/url-shortener-project/common/eventbus"
+	"github.com/Farhang-Osman/url-shortener-project/common/repository"
+	analyticspb "github.com/Farhang-Osman/url-shortener-project/pkg/proto/analyticspb"
+	eventspb "github.com/Farhang-Osman/url-shortener-project/pkg/proto/eventspb"
 )
 
 const (
-	kafkaBroker  = "localhost:9092" // Kafka broker address
 	createdTopic = "url-created-events"
 	clickTopic   = "url-click-events"
-)
-
-type URLCreatedEvent struct {
-	ShortCode string    `json:"short_code"`
-	LongURL   string    `json:"long_url"`
-	UserID    string    `json:"user_id"`
-	CreatedAt time.Time `json:"created_at"`
-}
+	deletedTopic = "url-deleted-events"
 
-type URLClickedEvent struct {
-	ShortCode string    `json:"short_code"`
-	ClickedAt time.Time `json:"clicked_at"`
-	UserAgent string    `json:"user_agent"`
-	Referer   string    `json:"referer"`
-	IPAddress string    `json:"ip_address"`
-}
+	// How long in-flight batches get to finish after SIGTERM
+	shutdownTimeout = 30 * time.Second
+)
 
 func main() {
+	var cfg Config
+	args := config.MustLoad(&cfg, "analytics-service")
+
 	// Initialize database connection pool
-	if err := db.InitDB(); err != nil {
+	if err := db.InitDB(cfg.Database.URL); err != nil {
 		log.Fatalf("failed to initialize database: %v", err)
 	}
 	defer db.CloseDB()
 
-	log.Println("Analytics Service started. Waiting for messages...")
-
-	ctx := context.Background()
-
-	// Kafka consumer for URL created events
-	createdReader := kafka.NewReader(
-		kafka.ReaderConfig{
-			Brokers:   []string{kafkaBroker},
-			Topic:     createdTopic,
-			GroupID:   "analytics-created-group",
-			Partition: 0,
-			MinBytes:  10e3, // 10KB
-			MaxBytes:  10e6, // 10MB
-			MaxWait:   1 * time.Second,
-			Dialer: &kafka.Dialer{
-				Timeout:   10 * time.Second,
-				DualStack: true,
-			},
-		},
-	)
-	defer createdReader.Close()
-
-	// Kafka consumer for URL click events
-	clickReader := kafka.NewReader(
-		kafka.ReaderConfig{
-			Brokers:   []string{kafkaBroker},
-			Topic:     clickTopic,
-			GroupID:   "analytics-click-group",
-			Partition: 0,
-			MinBytes:  10e3, // 10KB
-			MaxBytes:  10e6, // 10MB
-			MaxWait:   1 * time.Second,
-			Dialer: &kafka.Dialer{
-				Timeout:   10 * time.Second,
-				DualStack: true,
-			},
-		},
-	)
-	defer clickReader.Close()
-
-	go func() {
-		for {
-			msg, err := createdReader.FetchMessage(ctx)
-			if err != nil {
-				log.Printf("Error reading create message: %v", err)
-				time.Sleep(5 * time.Second) // Wait before retrying
-				continue
+	analytics := repository.NewPostgresAnalyticsRepository(db.DB)
+
+	// Subcommands:
+	//   analytics-service [flags] migrate up|down|status
+	//   analytics-service [flags] rebuild-rollups -from <RFC 3339> [-to <RFC 3339>]
+	//   analytics-service [flags] replay-dlq -topic <topic> [-max N] [-idle 10s]
+	if len(args) > 0 {
+		switch args[0] {
+		case "migrate":
+			if err := db.RunMigrateCommand(args[1:]); err != nil {
+				log.Fatalf("failed to migrate database: %v", err)
 			}
-
-			var event URLCreatedEvent
-			if err := json.Unmarshal(msg.Value, &event); err != nil {
-				log.Printf("Error unmarshalling created event: %v", err)
-				createdReader.CommitMessages(ctx, msg)
-				continue
+			return
+		case "rebuild-rollups":
+			if err := runRebuildRollups(analytics, args[1:]); err != nil {
+				log.Fatalf("failed to rebuild rollups: %v", err)
 			}
-
-			log.Printf("Received URL Created Event: ShortCode=%s, LongURL=%s", event.ShortCode, event.LongURL)
-
-			// Store in analytics table
-			_, err = db.DB.Exec(ctx,
-				"INSERT INTO analytics (event_type, short_code, long_url, user_id, timestamp) VALUES ($1, $2, $3, $4, $5)",
-				"url_created", event.ShortCode, event.LongURL, event.UserID, event.CreatedAt)
-			if err != nil {
-				log.Printf("Error storing created event in DB: %v", err)
-			} else {
-				log.Printf("Stored URL Created Event for short code: %s", event.ShortCode)
+			return
+		case "replay-dlq":
+			if err := runReplayDLQ(cfg.Kafka.Brokers, args[1:]); err != nil {
+				log.Fatalf("failed to replay dead-letter queue: %v", err)
 			}
+			return
+		default:
+			log.Fatalf("unknown command %q", args[0])
+		}
+	}
 
-			createdReader.CommitMessages(ctx, msg)
+	if cfg.Database.MigrateOnStartup {
+		if err := db.MigrateUp(context.Background()); err != nil {
+			log.Fatalf("failed to apply migrations: %v", err)
 		}
-	}()
+	}
 
-	go func() {
-		for {
-			msg, err := clickReader.FetchMessage(ctx)
-			if err != nil {
-				log.Printf("Error reading click message: %v", err)
-				time.Sleep(5 * time.Second) // Wait before retrying
-				continue
-			}
+	log.Println("Analytics Service started. Waiting for messages...")
 
-			var event URLClickedEvent
-			if err := json.Unmarshal(msg.Value, &event); err != nil {
-				log.Printf("Error unmarshalling click event: %v", err)
-				clickReader.CommitMessages(ctx, msg)
-				continue
-			}
+	// ctx is cancelled on SIGINT/SIGTERM; storeCtx only if the in-flight
+	// batches don't finish within shutdownTimeout
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	storeCtx, cancelStore := context.WithCancel(context.Background())
+	defer cancelStore()
+
+	// Consumer group readers: partitions are spread across every replica and
+	// rebalanced as replicas come and go. Producers key messages by short
+	// code, so each link's events stay ordered within one partition.
+	createdSub := newGroupSubscriber(cfg.Kafka.Brokers, createdTopic, "analytics-created-group")
+	clickSub := newGroupSubscriber(cfg.Kafka.Brokers, clickTopic, "analytics-click-group")
+	deletedSub := newGroupSubscriber(cfg.Kafka.Brokers, deletedTopic, "analytics-deleted-group")
+
+	log.Printf("Batching up to %d messages every %v", cfg.Batch.Size, cfg.Batch.FlushInterval)
+
+	// Messages that can't be stored go to <topic>.dlq instead of being dropped
+	publisher := eventbus.NewKafkaPublisher(cfg.Kafka.Brokers...)
+	defer publisher.Close()
+	dlq := newDeadLetterQueue(publisher)
+
+	createdConsumer := &batchConsumer[*eventspb.EventEnvelope]{
+		name:   "url created",
+		sub:    createdSub,
+		dlq:    dlq,
+		cfg:    cfg.Batch,
+		decode: decodeCreatedEvent,
+		store:  createdEventStore(analytics),
+	}
+	clickConsumer := &batchConsumer[*eventspb.EventEnvelope]{
+		name:   "url clicked",
+		sub:    clickSub,
+		dlq:    dlq,
+		cfg:    cfg.Batch,
+		decode: decodeClickEvent,
+		store:  clickEventStore(analytics),
+	}
+	deletedConsumer := &batchConsumer[*eventspb.EventEnvelope]{
+		name:   "url deleted",
+		sub:    deletedSub,
+		dlq:    dlq,
+		cfg:    cfg.Batch,
+		decode: decodeDeletedEvent,
+		store:  deletedEventStore(analytics),
+	}
 
-			log.Printf("Received URL Clicked Event: ShortCode=%s, IP=%s", event.ShortCode, event.IPAddress)
+	var consumers sync.WaitGroup
+	for _, c := range []*batchConsumer[*eventspb.EventEnvelope]{createdConsumer, clickConsumer, deletedConsumer} {
+		consumers.Add(1)
+		go func() {
+			defer consumers.Done()
+			c.run(ctx, storeCtx)
+		}()
+	}
 
-			// Store in analytics table
-			_, err = db.DB.Exec(ctx,
-				"INSERT INTO analytics (event_type, short_code, user_agent, referer, ip_address, timestamp) VALUES ($1, $2, $3, $4, $5, $6)",
-				"url_clicked", event.ShortCode, event.UserAgent, event.Referer, event.IPAddress, event.ClickedAt)
-			if err != nil {
-				log.Printf("Error storing click event in DB: %v", err)
-			} else {
-				log.Printf("Stored URL Clicked Event for short code: %s", event.ShortCode)
-			}
+	// Serve stats queries
+	lis, err := net.Listen("tcp", cfg.ListenAddr)
+	if err != nil {
+		log.Fatalf("failed to listen: %v", err)
+	}
 
-			clickReader.CommitMessages(ctx, msg)
+	s := grpc.NewServer()
+	analyticspb.RegisterAnalyticsServiceServer(s, newServer(analytics))
+
+	go func() {
+		log.Printf("Analytics Service listening at %v", lis.Addr())
+		if err := s.Serve(lis); err != nil {
+			log.Fatalf("failed to serve: %v", err)
 		}
 	}()
 
-	// Keep the main goroutine alive
-	select {}
+	<-ctx.Done()
+	log.Println("Shutting down analytics service...")
+	s.GracefulStop()
+
+	// Let the consumers store and commit what they already fetched
+	done := make(chan struct{})
+	go func() {
+		consumers.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(shutdownTimeout):
+		log.Printf("Consumers did not finish within %v, abandoning in-flight batches", shutdownTimeout)
+		cancelStore()
+		<-done
+	}
+
+	// Closing the subscribers leaves the consumer groups so the remaining
+	// replicas pick up these partitions straight away
+	for _, sub := range []eventbus.Subscriber{createdSub, clickSub, deletedSub} {
+		if err := sub.Close(); err != nil {
+			log.Printf("Error leaving consumer group: %v", err)
+		}
+	}
+	log.Println("Analytics service stopped")
+}
+
+// newGroupSubscriber returns a subscriber for topic on brokers that joins
+// consumer group groupID. Offsets are committed explicitly after each batch is stored, so a
+// batch redelivered after a rebalance is skipped by the event ID check.
+func newGroupSubscriber(brokers []string, topic, groupID string) *eventbus.KafkaSubscriber {
+	return eventbus.NewKafkaSubscriber(eventbus.KafkaSubscriberConfig{
+		Brokers:          brokers,
+		Topic:            topic,
+		GroupID:          groupID,
+		MinBytes:         10e3, // 10KB
+		MaxBytes:         10e6, // 10MB
+		MaxWait:          1 * time.Second,
+		RebalanceTimeout: shutdownTimeout,
+	})
 }
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/Farhang-Osman/url-shortener-project/common/eventbus"
+	"github.com/Farhang-Osman/url-shortener-project/common/events"
+	"github.com/Farhang-Osman/url-shortener-project/common/outbox"
+	"github.com/Farhang-Osman/url-shortener-project/common/repository"
+	analyticspb "github.com/Farhang-Osman/url-shortener-project/pkg/proto/analyticspb"
+	eventspb "github.com/Farhang-Osman/url-shortener-project/pkg/proto/eventspb"
+)
+
+// TestCreatedEventPipeline runs a url-created event from the publisher side
+// through the batch consumer on the in-memory bus into the in-memory
+// analytics repository, and reads it back through the gRPC server. A
+// malformed message on the same topic must end up in the dead-letter queue
+// without blocking it.
+func TestCreatedEventPipeline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	bus := eventbus.NewMemory()
+	publisher := bus.Publisher()
+
+	// Published the way shortener-service's outbox relay does
+	created := events.NewURLCreated(&eventspb.URLCreatedV1{
+		ShortCode: "abc123",
+		LongUrl:   "https://example.com/landing",
+		UserId:    "7c9e6679-7425-40de-944b-e07fc1f90ae7",
+		CreatedAt: timestamppb.Now(),
+	})
+	payload, err := events.Marshal(created)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = publisher.Publish(ctx,
+		eventbus.Message{Topic: createdTopic, Key: []byte("garbage"), Value: []byte("not an event")},
+		eventbus.Message{
+			Topic:   createdTopic,
+			Key:     []byte("abc123"),
+			Value:   payload,
+			Headers: map[string]string{events.HeaderContentType: events.ContentTypeProtobuf},
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	analytics := repository.NewMemoryAnalyticsRepository()
+	store := createdEventStore(analytics)
+	stored := make(chan []*eventspb.EventEnvelope, 1)
+	consumer := &batchConsumer[*eventspb.EventEnvelope]{
+		name:   "url created",
+		sub:    bus.Subscriber(createdTopic, "analytics-created-group"),
+		dlq:    newDeadLetterQueue(publisher),
+		cfg:    batchConfig{Size: 10, FlushInterval: 50 * time.Millisecond},
+		decode: decodeCreatedEvent,
+		store: func(ctx context.Context, envs []*eventspb.EventEnvelope) error {
+			if err := store(ctx, envs); err != nil {
+				return err
+			}
+			stored <- envs
+			return nil
+		},
+	}
+
+	runCtx, stop := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		consumer.run(runCtx, ctx)
+	}()
+
+	select {
+	case envs := <-stored:
+		if len(envs) != 1 {
+			t.Fatalf("stored %d events, want 1", len(envs))
+		}
+		got := envs[0].GetUrlCreated()
+		if envs[0].GetEventId() != created.GetEventId() || got.GetShortCode() != "abc123" ||
+			got.GetLongUrl() != "https://example.com/landing" || got.GetUserId() != "7c9e6679-7425-40de-944b-e07fc1f90ae7" {
+			t.Errorf("stored %v, want %v", envs[0], created)
+		}
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for the event to be stored")
+	}
+
+	// The link now belongs to its creator in analytics
+	stats, err := newServer(analytics).GetLinkStats(ctx, &analyticspb.GetLinkStatsRequest{
+		ShortCode: "abc123",
+		UserId:    "7c9e6679-7425-40de-944b-e07fc1f90ae7",
+	})
+	if err != nil {
+		t.Fatalf("GetLinkStats: %v", err)
+	}
+	if stats.GetShortCode() != "abc123" || stats.GetTotalClicks() != 0 {
+		t.Errorf("GetLinkStats = %v", stats)
+	}
+
+	dead, err := bus.Subscriber(createdTopic+dlqSuffix, "test").Fetch(ctx)
+	if err != nil {
+		t.Fatalf("reading dead-letter queue: %v", err)
+	}
+	if string(dead.Value) != "not an event" || dead.Headers[headerDLQReason] != reasonUndecodable {
+		t.Errorf("dead-lettered %q with reason %q", dead.Value, dead.Headers[headerDLQReason])
+	}
+
+	stop()
+	<-done
+	consumer.sub.Close()
+
+	// Both messages were committed, so the group has nothing left to read
+	fetchCtx, cancelFetch := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancelFetch()
+	if msg, err := bus.Subscriber(createdTopic, "analytics-created-group").Fetch(fetchCtx); err == nil {
+		t.Errorf("message at offset %d was not committed", msg.Offset)
+	}
+}
+
+// TestOutboxToAnalytics stores a URL the way shortener-service's ShortenURL
+// does, with its url-created event in the outbox, and checks that the relay
+// and the batch consumer carry the event over the in-memory bus into the
+// analytics repository
+func TestOutboxToAnalytics(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	const userID = "7c9e6679-7425-40de-944b-e07fc1f90ae7"
+	bus := eventbus.NewMemory()
+	urls := repository.NewMemoryURLRepository()
+	analytics := repository.NewMemoryAnalyticsRepository()
+
+	relay := outbox.NewRelay(urls, bus.Publisher())
+	defer relay.Close()
+
+	consumer := &batchConsumer[*eventspb.EventEnvelope]{
+		name:   "url created",
+		sub:    bus.Subscriber(outbox.TopicURLCreated, "analytics-created-group"),
+		dlq:    newDeadLetterQueue(bus.Publisher()),
+		cfg:    batchConfig{Size: 10, FlushInterval: 50 * time.Millisecond},
+		decode: decodeCreatedEvent,
+		store:  createdEventStore(analytics),
+	}
+	runCtx, stop := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		consumer.run(runCtx, ctx)
+	}()
+	defer func() {
+		stop()
+		<-done
+		consumer.sub.Close()
+	}()
+
+	u := &repository.URL{
+		ShortCode: "launch",
+		LongURL:   "https://example.com/launch",
+		UserID:    userID,
+		Active:    true,
+		CreatedAt: time.Now(),
+	}
+	if err := urls.Create(ctx, u, outbox.URLCreated(u.ShortCode, u.LongURL, u.UserID, u.CreatedAt)); err != nil {
+		t.Fatal(err)
+	}
+
+	for {
+		owner, err := analytics.LinkOwner(ctx, "launch")
+		if err == nil {
+			if owner != userID {
+				t.Fatalf("LinkOwner = %q, want %q", owner, userID)
+			}
+			break
+		}
+		select {
+		case <-ctx.Done():
+			t.Fatalf("timed out waiting for the link to reach analytics: %v", err)
+		case <-time.After(20 * time.Millisecond):
+		}
+	}
+
+	summary, err := analytics.UserSummary(ctx, userID, repository.StatsQuery{
+		From: time.Now().Add(-time.Hour), To: time.Now(), TopLimit: 10,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.TotalLinks != 1 {
+		t.Errorf("UserSummary counts %d links, want 1", summary.TotalLinks)
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Farhang-Osman/url-shortener-project/common/repository"
+)
+
+// runRebuildRollups implements the "rebuild-rollups" subcommand
+func runRebuildRollups(analytics repository.AnalyticsRepository, args []string) error {
+	fs := flag.NewFlagSet("rebuild-rollups", flag.ExitOnError)
+	fromFlag := fs.String("from", "", "start of the range to rebuild, RFC 3339 (required)")
+	toFlag := fs.String("to", "", "end of the range to rebuild, RFC 3339 (default now)")
+	fs.Parse(args)
+
+	if *fromFlag == "" {
+		return fmt.Errorf("-from is required")
+	}
+	from, err := time.Parse(time.RFC3339, *fromFlag)
+	if err != nil {
+		return fmt.Errorf("invalid -from: %v", err)
+	}
+	to := time.Now()
+	if *toFlag != "" {
+		if to, err = time.Parse(time.RFC3339, *toFlag); err != nil {
+			return fmt.Errorf("invalid -to: %v", err)
+		}
+	}
+	if !from.Before(to) {
+		return fmt.Errorf("-from must be before -to")
+	}
+
+	from, to, total, err := analytics.RebuildRollups(context.Background(), from, to)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Rebuilt click rollups for %s to %s from %d click events", from.Format(time.RFC3339), to.Format(time.RFC3339), total)
+	return nil
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Farhang-Osman/url-shortener-project/common/repository"
+	analyticspb "github.com/Farhang-Osman/url-shortener-project/pkg/proto/analyticspb"
+)
+
+const (
+	defaultStatsWindow = 30 * 24 * time.Hour
+	defaultTopLimit    = 10
+	maxTopLimit        = 100
+)
+
+type server struct {
+	analyticspb.UnimplementedAnalyticsServiceServer
+	analytics repository.AnalyticsRepository
+}
+
+func newServer(analytics repository.AnalyticsRepository) *server {
+	return &server{analytics: analytics}
+}
+
+// statsRange resolves the optional from/to/top_limit request fields
+func statsRange(from, to string, topLimit int32) (time.Time, time.Time, int, error) {
+	end := time.Now()
+	if to != "" {
+		t, err := time.Parse(time.RFC3339, to)
+		if err != nil {
+			return time.Time{}, time.Time{}, 0, status.Errorf(codes.InvalidArgument, "invalid to format: %v", err)
+		}
+		end = t
+	}
+
+	start := end.Add(-defaultStatsWindow)
+	if from != "" {
+		t, err := time.Parse(time.RFC3339, from)
+		if err != nil {
+			return time.Time{}, time.Time{}, 0, status.Errorf(codes.InvalidArgument, "invalid from format: %v", err)
+		}
+		start = t
+	}
+
+	if !start.Before(end) {
+		return time.Time{}, time.Time{}, 0, status.Errorf(codes.InvalidArgument, "from must be before to")
+	}
+
+	limit := int(topLimit)
+	if limit <= 0 {
+		limit = defaultTopLimit
+	}
+	if limit > maxTopLimit {
+		limit = maxTopLimit
+	}
+
+	return start, end, limit, nil
+}
+
+// granularity maps a requested granularity to a repository one
+func granularity(g analyticspb.Granularity) string {
+	switch g {
+	case analyticspb.Granularity_GRANULARITY_HOUR:
+		return repository.GranularityHour
+	case analyticspb.Granularity_GRANULARITY_MONTH:
+		return repository.GranularityMonth
+	default:
+		return repository.GranularityDay
+	}
+}
+
+func timeSeriesProto(series []repository.TimeBucket) []*analyticspb.TimeBucket {
+	buckets := make([]*analyticspb.TimeBucket, 0, len(series))
+	for _, b := range series {
+		buckets = append(buckets, &analyticspb.TimeBucket{
+			BucketStart:  b.Start.UTC().Format(time.RFC3339),
+			Clicks:       b.Clicks,
+			UniqueClicks: b.UniqueClicks,
+		})
+	}
+	return buckets
+}
+
+func countsProto(counts []repository.CountEntry) []*analyticspb.CountEntry {
+	entries := make([]*analyticspb.CountEntry, 0, len(counts))
+	for _, c := range counts {
+		entries = append(entries, &analyticspb.CountEntry{Value: c.Value, Count: c.Count})
+	}
+	return entries
+}
+
+func (s *server) GetLinkStats(ctx context.Context, req *analyticspb.GetLinkStatsRequest) (*analyticspb.GetLinkStatsResponse, error) {
+	log.Printf("Received GetLinkStats request: %v\n", req.GetShortCode())
+
+	start, end, limit, err := statsRange(req.GetFrom(), req.GetTo(), req.GetTopLimit())
+	if err != nil {
+		return nil, err
+	}
+
+	ownerID, err := s.analytics.LinkOwner(ctx, req.GetShortCode())
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, status.Errorf(codes.NotFound, "short URL not found")
+		}
+		return nil, status.Errorf(codes.Internal, "database error: %v", err)
+	}
+	if req.GetUserId() == "" || ownerID != req.GetUserId() {
+		return nil, status.Errorf(codes.PermissionDenied, "you do not own this short URL")
+	}
+
+	stats, err := s.analytics.LinkStats(ctx, req.GetShortCode(), repository.StatsQuery{
+		From:        start,
+		To:          end,
+		Granularity: granularity(req.GetGranularity()),
+		TopLimit:    limit,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "database error: %v", err)
+	}
+
+	return &analyticspb.GetLinkStatsResponse{
+		ShortCode:         req.GetShortCode(),
+		TotalClicks:       stats.TotalClicks,
+		UniqueClicks:      stats.UniqueClicks,
+		Series:            timeSeriesProto(stats.Series),
+		TopReferers:       countsProto(stats.TopReferers),
+		TopUserAgents:     countsProto(stats.TopUserAgents),
+		TopCountries:      countsProto(stats.TopCountries),
+		TopRefererDomains: countsProto(stats.TopRefererDomains),
+		TopDeviceClasses:  countsProto(stats.TopDeviceClasses),
+	}, nil
+}
+
+func (s *server) GetUserSummary(ctx context.Context, req *analyticspb.GetUserSummaryRequest) (*analyticspb.GetUserSummaryResponse, error) {
+	log.Printf("Received GetUserSummary request: %v\n", req.GetUserId())
+
+	if req.GetUserId() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "user_id is required")
+	}
+
+	start, end, limit, err := statsRange(req.GetFrom(), req.GetTo(), req.GetTopLimit())
+	if err != nil {
+		return nil, err
+	}
+
+	summary, err := s.analytics.UserSummary(ctx, req.GetUserId(), repository.StatsQuery{
+		From:        start,
+		To:          end,
+		Granularity: granularity(req.GetGranularity()),
+		TopLimit:    limit,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "database error: %v", err)
+	}
+
+	res := &analyticspb.GetUserSummaryResponse{
+		TotalLinks:   summary.TotalLinks,
+		TotalClicks:  summary.TotalClicks,
+		UniqueClicks: summary.UniqueClicks,
+		Series:       timeSeriesProto(summary.Series),
+	}
+	for _, l := range summary.TopLinks {
+		res.TopLinks = append(res.TopLinks, &analyticspb.LinkClicks{
+			ShortCode:    l.ShortCode,
+			LongUrl:      l.LongURL,
+			Clicks:       l.Clicks,
+			UniqueClicks: l.UniqueClicks,
+		})
+	}
+
+	return res, nil
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/Farhang-Osman/url-shortener-project/common/eventbus"
+	"github.com/Farhang-Osman/url-shortener-project/common/events"
+	"github.com/Farhang-Osman/url-shortener-project/common/repository"
+	eventspb "github.com/Farhang-Osman/url-shortener-project/pkg/proto/eventspb"
+)
+
+// decodeCreatedEvent decodes a url-created message, either a protobuf
+// envelope or a legacy JSON event
+func decodeCreatedEvent(msg eventbus.Message) (*eventspb.EventEnvelope, error) {
+	return decodeEvent(msg, events.TypeURLCreated)
+}
+
+// decodeClickEvent decodes a url-click message, either a protobuf envelope or
+// a legacy JSON event
+func decodeClickEvent(msg eventbus.Message) (*eventspb.EventEnvelope, error) {
+	return decodeEvent(msg, events.TypeURLClicked)
+}
+
+// decodeDeletedEvent decodes a url-deleted message, either a protobuf envelope
+// or a legacy JSON event. Events that don't say when the link was deleted are
+// rejected: deleting up to a made-up time would silently keep its analytics.
+func decodeDeletedEvent(msg eventbus.Message) (*eventspb.EventEnvelope, error) {
+	env, err := decodeEvent(msg, events.TypeURLDeleted)
+	if err != nil {
+		return nil, err
+	}
+	if env.GetUrlDeleted().GetShortCode() == "" {
+		return nil, fmt.Errorf("url_deleted event has no short code")
+	}
+	if _, ok := deletionTime(env); !ok {
+		return nil, fmt.Errorf("url_deleted event has neither deleted_at nor a timestamp")
+	}
+	return env, nil
+}
+
+// deletionTime returns when the link of a url_deleted event was deleted: its
+// deleted_at, or the envelope timestamp for producers that leave it out.
+// Unset and zero times, as legacy JSON without the field decodes to, don't
+// count.
+func deletionTime(env *eventspb.EventEnvelope) (time.Time, bool) {
+	for _, ts := range []*timestamppb.Timestamp{env.GetUrlDeleted().GetDeletedAt(), env.GetTimestamp()} {
+		if ts.IsValid() && ts.GetSeconds() > 0 {
+			return ts.AsTime(), true
+		}
+	}
+	return time.Time{}, false
+}
+
+func decodeEvent(msg eventbus.Message, eventType string) (*eventspb.EventEnvelope, error) {
+	env, err := events.Decode(msg.Value, msg.Headers[events.HeaderContentType], eventType)
+	if err != nil {
+		return nil, err
+	}
+	if env.GetEventType() != eventType {
+		return nil, fmt.Errorf("expected %s event, got %s", eventType, env.GetEventType())
+	}
+	return env, nil
+}
+
+// createdEventStore returns a batch consumer store function that writes
+// url_created events to analytics
+func createdEventStore(analytics repository.AnalyticsRepository) func(context.Context, []*eventspb.EventEnvelope) error {
+	return func(ctx context.Context, envs []*eventspb.EventEnvelope) error {
+		events := make([]repository.CreatedEvent, 0, len(envs))
+		for _, env := range envs {
+			created := env.GetUrlCreated()
+			events = append(events, repository.CreatedEvent{
+				EventID:   env.GetEventId(),
+				ShortCode: created.GetShortCode(),
+				LongURL:   created.GetLongUrl(),
+				UserID:    created.GetUserId(),
+				CreatedAt: created.GetCreatedAt().AsTime(),
+			})
+		}
+
+		inserted, err := analytics.InsertCreated(ctx, events)
+		if err != nil {
+			return err
+		}
+		if skipped := len(events) - inserted; skipped > 0 {
+			log.Printf("Skipped %d duplicate url created events", skipped)
+		}
+		return nil
+	}
+}
+
+// clickEventStore returns a batch consumer store function that writes
+// url_clicked events to analytics and the click rollups
+func clickEventStore(analytics repository.AnalyticsRepository) func(context.Context, []*eventspb.EventEnvelope) error {
+	return func(ctx context.Context, envs []*eventspb.EventEnvelope) error {
+		events := make([]repository.ClickEvent, 0, len(envs))
+		for _, env := range envs {
+			click := env.GetUrlClicked()
+			events = append(events, repository.ClickEvent{
+				EventID:   env.GetEventId(),
+				ShortCode: click.GetShortCode(),
+				UserAgent: click.GetUserAgent(),
+				Referer:   click.GetReferer(),
+				IPAddress: click.GetIpAddress(),
+				Country:   click.GetCountry(),
+				ClickedAt: click.GetClickedAt().AsTime(),
+			})
+		}
+
+		inserted, err := analytics.InsertClicks(ctx, events)
+		if err != nil {
+			return err
+		}
+		if skipped := len(events) - inserted; skipped > 0 {
+			log.Printf("Skipped %d duplicate url clicked events", skipped)
+		}
+		return nil
+	}
+}
+
+// deletedEventStore returns a batch consumer store function that removes the
+// analytics of deleted links. Deleting is idempotent, so redelivered events
+// need no deduplication.
+func deletedEventStore(analytics repository.AnalyticsRepository) func(context.Context, []*eventspb.EventEnvelope) error {
+	return func(ctx context.Context, envs []*eventspb.EventEnvelope) error {
+		for _, env := range envs {
+			// Checked by decodeDeletedEvent
+			deletedAt, _ := deletionTime(env)
+			if err := analytics.DeleteLink(ctx, env.GetUrlDeleted().GetShortCode(), deletedAt); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
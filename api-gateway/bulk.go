@@ -0,0 +1,207 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	shortenerpb "github.com/Farhang-Osman/url-shortener-project/pkg/proto/shortenerpb"
+)
+
+const maxBulkBodyBytes = 10 << 20
+
+// bulkURL is one row of a bulk upload
+type bulkURL struct {
+	LongURL     string   `json:"long_url"`
+	CustomAlias string   `json:"custom_alias"`
+	ExpiresAt   string   `json:"expires_at"`
+	Tags        []string `json:"tags"`
+}
+
+// bulkCSVColumns are the CSV columns in the order assumed without a header row
+var bulkCSVColumns = []string{"long_url", "custom_alias", "expires_at", "tags"}
+
+// parseBulkCSV reads rows of long_url, custom_alias, expires_at and tags.
+// Trailing columns may be left out. A header row naming the columns is
+// optional and allows any column order. Tags are separated by commas or
+// semicolons.
+func parseBulkCSV(r io.Reader) ([]bulkURL, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	columns := bulkCSVColumns
+	if len(records) > 0 && strings.EqualFold(strings.TrimSpace(records[0][0]), "long_url") {
+		columns = make([]string, len(records[0]))
+		for i, name := range records[0] {
+			name = strings.ToLower(strings.TrimSpace(name))
+			switch name {
+			case "long_url", "custom_alias", "expires_at", "tags":
+			default:
+				return nil, fmt.Errorf("unknown column %q", name)
+			}
+			columns[i] = name
+		}
+		records = records[1:]
+	}
+
+	urls := make([]bulkURL, 0, len(records))
+	for i, record := range records {
+		if len(record) > len(columns) {
+			return nil, fmt.Errorf("record %d has %d fields, want at most %d", i+1, len(record), len(columns))
+		}
+		var u bulkURL
+		for j, value := range record {
+			value = strings.TrimSpace(value)
+			switch columns[j] {
+			case "long_url":
+				u.LongURL = value
+			case "custom_alias":
+				u.CustomAlias = value
+			case "expires_at":
+				u.ExpiresAt = value
+			case "tags":
+				u.Tags = strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ';' })
+			}
+		}
+		urls = append(urls, u)
+	}
+	return urls, nil
+}
+
+// wantsCSV picks the result format: the one named in the Accept header, or
+// else the format of the upload
+func wantsCSV(r *http.Request, uploadedCSV bool) bool {
+	accept := r.Header.Get("Accept")
+	switch {
+	case strings.Contains(accept, "text/csv"):
+		return true
+	case strings.Contains(accept, "application/json"):
+		return false
+	default:
+		return uploadedCSV
+	}
+}
+
+// BulkShortenURLs handles shortening many URLs at once. The body is a JSON
+// array or a CSV file; the result reports the outcome of every row, numbered
+// from 1, as JSON or CSV.
+func (g *APIGateway) BulkShortenURLs(w http.ResponseWriter, r *http.Request) {
+	// Get user_id from context (set by AuthMiddleware)
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok || userID == "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(map[string]string{"error": "User not authenticated"})
+		return
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	uploadedCSV := mediaType == "text/csv"
+	if !uploadedCSV && mediaType != "application/json" && mediaType != "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Content-Type must be application/json or text/csv"})
+		return
+	}
+
+	// Read the whole upload first so a malformed file creates no links at all
+	body := http.MaxBytesReader(w, r.Body, maxBulkBodyBytes)
+	var urls []bulkURL
+	var err error
+	if uploadedCSV {
+		urls, err = parseBulkCSV(body)
+	} else {
+		err = json.NewDecoder(body).Decode(&urls)
+	}
+	if err == nil && len(urls) == 0 {
+		err = errors.New("no URLs to shorten")
+	}
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	stream, err := g.shortenerClient.BulkShortenURLs(r.Context())
+	if err == nil {
+		for _, u := range urls {
+			// On failure the stream is broken; CloseAndRecv reports why
+			if err := stream.Send(&shortenerpb.ShortenURLRequest{
+				LongUrl:     u.LongURL,
+				CustomAlias: u.CustomAlias,
+				ExpiresAt:   u.ExpiresAt,
+				UserId:      userID,
+				Tags:        u.Tags,
+			}); err != nil {
+				break
+			}
+		}
+	}
+	var res *shortenerpb.BulkShortenURLsResponse
+	if err == nil {
+		res, err = stream.CloseAndRecv()
+	}
+	if err != nil {
+		log.Printf("Error from Shortener Service (BulkShortenURLs): %v", err)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(httpStatusFromGRPC(err))
+		json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("Bulk URL shortening failed: %v", err)})
+		return
+	}
+
+	if wantsCSV(r, uploadedCSV) {
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", `attachment; filename="bulk-results.csv"`)
+		cw := csv.NewWriter(w)
+		cw.Write([]string{"row", "long_url", "custom_alias", "short_code", "short_url", "error_code", "error"})
+		for _, result := range res.GetResults() {
+			u := urls[result.GetRow()]
+			cw.Write([]string{
+				strconv.Itoa(int(result.GetRow()) + 1),
+				u.LongURL,
+				u.CustomAlias,
+				result.GetShortCode(),
+				result.GetShortUrl(),
+				result.GetErrorCode(),
+				result.GetError(),
+			})
+		}
+		cw.Flush()
+		return
+	}
+
+	results := make([]map[string]interface{}, 0, len(res.GetResults()))
+	for _, result := range res.GetResults() {
+		u := urls[result.GetRow()]
+		results = append(results, map[string]interface{}{
+			"row":          result.GetRow() + 1,
+			"long_url":     u.LongURL,
+			"custom_alias": u.CustomAlias,
+			"short_code":   result.GetShortCode(),
+			"short_url":    result.GetShortUrl(),
+			"error_code":   result.GetErrorCode(),
+			"error":        result.GetError(),
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"created": res.GetCreated(),
+		"failed":  res.GetFailed(),
+		"results": results,
+	})
+}
@@ -0,0 +1,10 @@
+package main
+
+// Config is loaded from the environment, flags and an optional YAML file;
+// see package config
+type Config struct {
+	ListenAddr           string `yaml:"listen_addr" env:"LISTEN_ADDR" flag:"listen" default:":8080" usage:"HTTP listen address"`
+	UserServiceAddr      string `yaml:"user_service_addr" env:"USER_SERVICE_ADDR" flag:"user-service-addr" default:"localhost:50051" usage:"user-service gRPC address"`
+	ShortenerServiceAddr string `yaml:"shortener_service_addr" env:"SHORTENER_SERVICE_ADDR" flag:"shortener-service-addr" default:"localhost:50052" usage:"shortener-service gRPC address"`
+	AnalyticsServiceAddr string `yaml:"analytics_service_addr" env:"ANALYTICS_SERVICE_ADDR" flag:"analytics-service-addr" default:"localhost:50053" usage:"analytics-service gRPC address"`
+}
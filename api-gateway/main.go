@@ -5,32 +5,35 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"math"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 
 	"github.com/gorilla/mux"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 
+	"github.com/Farhang-Osman/url-shortener-project/common/config"
+	analyticspb "github.com/Farhang-Osman/url-shortener-project/pkg/proto/analyticspb"
 	shortenerpb "github.com/Farhang-Osman/url-shortener-project/pkg/proto/shortenerpb"
 	userpb "github.com/Farhang-Osman/url-shortener-project/pkg/proto/userpb"
 )
 
-const ( // gRPC service addresses
-	userServiceAddress      = "localhost:50051"
-	shortenerServiceAddress = "localhost:50052"
-	jwtSecret               = "your-secret-key-change-this-in-production" // Must match User Service
-)
-
 type APIGateway struct {
 	userClient      userpb.UserServiceClient
 	shortenerClient shortenerpb.ShortenerServiceClient
+	analyticsClient analyticspb.AnalyticsServiceClient
 }
 
-func NewAPIGateway(userConn *grpc.ClientConn, shortenerConn *grpc.ClientConn) *APIGateway {
+func NewAPIGateway(userConn *grpc.ClientConn, shortenerConn *grpc.ClientConn, analyticsConn *grpc.ClientConn) *APIGateway {
 	return &APIGateway{
 		userClient:      userpb.NewUserServiceClient(userConn),
 		shortenerClient: shortenerpb.NewShortenerServiceClient(shortenerConn),
+		analyticsClient: analyticspb.NewAnalyticsServiceClient(analyticsConn),
 	}
 }
 
@@ -139,7 +142,7 @@ func (g *APIGateway) ShortenURL(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		log.Printf("Error from Shortener Service (ShortenURL): %v", err)
 		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
+		w.WriteHeader(httpStatusFromGRPC(err))
 		json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("URL shortening failed: %v", err)})
 		return
 	}
@@ -176,7 +179,7 @@ func (g *APIGateway) UpdateURLDestination(w http.ResponseWriter, r *http.Request
 	if err != nil {
 		log.Printf("Error from Shortener Service (UpdateURLDestination): %v", err)
 		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
+		w.WriteHeader(httpStatusFromGRPC(err))
 		json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("URL update failed: %v", err)})
 		return
 	}
@@ -185,21 +188,495 @@ func (g *APIGateway) UpdateURLDestination(w http.ResponseWriter, r *http.Request
 	json.NewEncoder(w).Encode(map[string]string{"short_code": res.GetShortCode(), "message": res.GetMessage()})
 }
 
+// ListURLs handles listing the caller's short URLs one page at a time
+func (g *APIGateway) ListURLs(w http.ResponseWriter, r *http.Request) {
+	// Get user_id from context (set by AuthMiddleware)
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok || userID == "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(map[string]string{"error": "User not authenticated"})
+		return
+	}
+
+	req, err := parseListURLsQuery(r.URL.Query())
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	req.UserId = userID
+
+	res, err := g.shortenerClient.ListURLs(r.Context(), req)
+	if err != nil {
+		log.Printf("Error from Shortener Service (ListURLs): %v", err)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(httpStatusFromGRPC(err))
+		json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("Listing URLs failed: %v", err)})
+		return
+	}
+
+	urls := make([]map[string]interface{}, 0, len(res.GetUrls()))
+	for _, u := range res.GetUrls() {
+		urls = append(urls, urlInfoJSON(u))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"urls": urls, "next_page_token": res.GetNextPageToken()})
+}
+
+// GetURLInfo handles fetching the full metadata of one of the caller's short URLs
+func (g *APIGateway) GetURLInfo(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	shortCode := vars["shortCode"]
+
+	// Get user_id from context (set by AuthMiddleware)
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok || userID == "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(map[string]string{"error": "User not authenticated"})
+		return
+	}
+
+	res, err := g.shortenerClient.GetURLInfo(r.Context(), &shortenerpb.GetURLInfoRequest{
+		ShortCode: shortCode,
+		UserId:    userID,
+	})
+	if err != nil {
+		log.Printf("Error from Shortener Service (GetURLInfo): %v", err)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(httpStatusFromGRPC(err))
+		json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("Fetching URL failed: %v", err)})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(urlInfoJSON(res))
+}
+
+// DeleteURL handles permanently deleting one of the caller's short URLs
+func (g *APIGateway) DeleteURL(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	shortCode := vars["shortCode"]
+
+	// Get user_id from context (set by AuthMiddleware)
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok || userID == "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(map[string]string{"error": "User not authenticated"})
+		return
+	}
+
+	res, err := g.shortenerClient.DeleteURL(r.Context(), &shortenerpb.DeleteURLRequest{
+		ShortCode: shortCode,
+		UserId:    userID,
+	})
+	if err != nil {
+		log.Printf("Error from Shortener Service (DeleteURL): %v", err)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(httpStatusFromGRPC(err))
+		json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("URL deletion failed: %v", err)})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"short_code": res.GetShortCode(), "message": res.GetMessage()})
+}
+
+// SetURLActive handles disabling or re-enabling one of the caller's short URLs
+func (g *APIGateway) SetURLActive(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	shortCode := vars["shortCode"]
+
+	var body struct {
+		Active *bool `json:"active"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Active == nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": `request body must be {"active": true|false}`})
+		return
+	}
+
+	// Get user_id from context (set by AuthMiddleware)
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok || userID == "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(map[string]string{"error": "User not authenticated"})
+		return
+	}
+
+	res, err := g.shortenerClient.SetURLActive(r.Context(), &shortenerpb.SetURLActiveRequest{
+		ShortCode: shortCode,
+		Active:    *body.Active,
+		UserId:    userID,
+	})
+	if err != nil {
+		log.Printf("Error from Shortener Service (SetURLActive): %v", err)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(httpStatusFromGRPC(err))
+		json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("URL update failed: %v", err)})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"short_code": res.GetShortCode(),
+		"active":     res.GetActive(),
+		"message":    res.GetMessage(),
+	})
+}
+
+// ListURLRevisions handles listing the destination history of a short URL
+func (g *APIGateway) ListURLRevisions(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	shortCode := vars["shortCode"]
+
+	// Get user_id from context (set by AuthMiddleware)
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok || userID == "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(map[string]string{"error": "User not authenticated"})
+		return
+	}
+
+	res, err := g.shortenerClient.ListURLRevisions(r.Context(), &shortenerpb.ListURLRevisionsRequest{
+		ShortCode: shortCode,
+		UserId:    userID,
+	})
+	if err != nil {
+		log.Printf("Error from Shortener Service (ListURLRevisions): %v", err)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(httpStatusFromGRPC(err))
+		json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("Listing URL revisions failed: %v", err)})
+		return
+	}
+
+	revisions := make([]map[string]string, 0, len(res.GetRevisions()))
+	for _, rev := range res.GetRevisions() {
+		revisions = append(revisions, map[string]string{
+			"revision_id":       rev.GetRevisionId(),
+			"previous_long_url": rev.GetPreviousLongUrl(),
+			"new_long_url":      rev.GetNewLongUrl(),
+			"changed_by":        rev.GetChangedBy(),
+			"changed_at":        rev.GetChangedAt(),
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"short_code": shortCode, "revisions": revisions})
+}
+
+// RestoreURLRevision handles restoring a short URL to the destination it had before a revision
+func (g *APIGateway) RestoreURLRevision(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	shortCode := vars["shortCode"]
+	revisionID := vars["revisionId"]
+
+	// Get user_id from context (set by AuthMiddleware)
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok || userID == "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(map[string]string{"error": "User not authenticated"})
+		return
+	}
+
+	res, err := g.shortenerClient.RestoreURLRevision(r.Context(), &shortenerpb.RestoreURLRevisionRequest{
+		ShortCode:  shortCode,
+		RevisionId: revisionID,
+		UserId:     userID,
+	})
+	if err != nil {
+		log.Printf("Error from Shortener Service (RestoreURLRevision): %v", err)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(httpStatusFromGRPC(err))
+		json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("URL restore failed: %v", err)})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"short_code": res.GetShortCode(), "message": res.GetMessage()})
+}
+
+// GetLinkStats handles fetching click statistics for one of the caller's short URLs
+func (g *APIGateway) GetLinkStats(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	shortCode := vars["shortCode"]
+
+	// Get user_id from context (set by AuthMiddleware)
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok || userID == "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(map[string]string{"error": "User not authenticated"})
+		return
+	}
+
+	query := r.URL.Query()
+	granularity, err := parseGranularity(query.Get("granularity"))
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	limit, _ := strconv.Atoi(query.Get("limit"))
+
+	res, err := g.analyticsClient.GetLinkStats(r.Context(), &analyticspb.GetLinkStatsRequest{
+		ShortCode:   shortCode,
+		UserId:      userID,
+		Granularity: granularity,
+		From:        query.Get("from"),
+		To:          query.Get("to"),
+		TopLimit:    int32(limit),
+	})
+	if err != nil {
+		log.Printf("Error from Analytics Service (GetLinkStats): %v", err)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(httpStatusFromGRPC(err))
+		json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("Fetching link stats failed: %v", err)})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"short_code":          res.GetShortCode(),
+		"total_clicks":        res.GetTotalClicks(),
+		"unique_clicks":       res.GetUniqueClicks(),
+		"series":              timeSeriesJSON(res.GetSeries()),
+		"top_referers":        countsJSON(res.GetTopReferers()),
+		"top_user_agents":     countsJSON(res.GetTopUserAgents()),
+		"top_countries":       countsJSON(res.GetTopCountries()),
+		"top_referer_domains": countsJSON(res.GetTopRefererDomains()),
+		"top_device_classes":  countsJSON(res.GetTopDeviceClasses()),
+	})
+}
+
+// GetUserSummary handles fetching click statistics across all of the caller's short URLs
+func (g *APIGateway) GetUserSummary(w http.ResponseWriter, r *http.Request) {
+	// Get user_id from context (set by AuthMiddleware)
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok || userID == "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(map[string]string{"error": "User not authenticated"})
+		return
+	}
+
+	query := r.URL.Query()
+	granularity, err := parseGranularity(query.Get("granularity"))
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	limit, _ := strconv.Atoi(query.Get("limit"))
+
+	res, err := g.analyticsClient.GetUserSummary(r.Context(), &analyticspb.GetUserSummaryRequest{
+		UserId:      userID,
+		Granularity: granularity,
+		From:        query.Get("from"),
+		To:          query.Get("to"),
+		TopLimit:    int32(limit),
+	})
+	if err != nil {
+		log.Printf("Error from Analytics Service (GetUserSummary): %v", err)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(httpStatusFromGRPC(err))
+		json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("Fetching stats summary failed: %v", err)})
+		return
+	}
+
+	topLinks := make([]map[string]interface{}, 0, len(res.GetTopLinks()))
+	for _, l := range res.GetTopLinks() {
+		topLinks = append(topLinks, map[string]interface{}{
+			"short_code":    l.GetShortCode(),
+			"long_url":      l.GetLongUrl(),
+			"clicks":        l.GetClicks(),
+			"unique_clicks": l.GetUniqueClicks(),
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"total_links":   res.GetTotalLinks(),
+		"total_clicks":  res.GetTotalClicks(),
+		"unique_clicks": res.GetUniqueClicks(),
+		"series":        timeSeriesJSON(res.GetSeries()),
+		"top_links":     topLinks,
+	})
+}
+
+// parseListURLsQuery maps the GET /auth/urls query parameters to a request:
+// page_size, page_token, sort (created_at, click_count or last_accessed),
+// order (asc or desc), expired and active (true or false), created_from,
+// created_to and q, a search string
+func parseListURLsQuery(query url.Values) (*shortenerpb.ListURLsRequest, error) {
+	req := &shortenerpb.ListURLsRequest{
+		PageToken:   query.Get("page_token"),
+		CreatedFrom: query.Get("created_from"),
+		CreatedTo:   query.Get("created_to"),
+		Query:       query.Get("q"),
+	}
+
+	if v := query.Get("page_size"); v != "" {
+		size, err := strconv.Atoi(v)
+		if err != nil || size < 1 {
+			return nil, fmt.Errorf("page_size must be a positive integer")
+		}
+		req.PageSize = int32(min(size, math.MaxInt32))
+	}
+
+	switch query.Get("sort") {
+	case "", "created_at":
+		req.SortBy = shortenerpb.URLSortField_URL_SORT_FIELD_CREATED_AT
+	case "click_count":
+		req.SortBy = shortenerpb.URLSortField_URL_SORT_FIELD_CLICK_COUNT
+	case "last_accessed":
+		req.SortBy = shortenerpb.URLSortField_URL_SORT_FIELD_LAST_ACCESSED
+	default:
+		return nil, fmt.Errorf("sort must be one of created_at, click_count or last_accessed")
+	}
+
+	switch query.Get("order") {
+	case "", "desc":
+		req.Order = shortenerpb.SortOrder_SORT_ORDER_DESC
+	case "asc":
+		req.Order = shortenerpb.SortOrder_SORT_ORDER_ASC
+	default:
+		return nil, fmt.Errorf("order must be asc or desc")
+	}
+
+	switch query.Get("expired") {
+	case "":
+	case "true":
+		req.Expiry = shortenerpb.ExpiryFilter_EXPIRY_FILTER_EXPIRED
+	case "false":
+		req.Expiry = shortenerpb.ExpiryFilter_EXPIRY_FILTER_UNEXPIRED
+	default:
+		return nil, fmt.Errorf("expired must be true or false")
+	}
+
+	switch query.Get("active") {
+	case "":
+	case "true":
+		req.Active = shortenerpb.ActiveFilter_ACTIVE_FILTER_ACTIVE
+	case "false":
+		req.Active = shortenerpb.ActiveFilter_ACTIVE_FILTER_DISABLED
+	default:
+		return nil, fmt.Errorf("active must be true or false")
+	}
+
+	return req, nil
+}
+
+func urlInfoJSON(u *shortenerpb.URLInfo) map[string]interface{} {
+	return map[string]interface{}{
+		"short_code":    u.GetShortCode(),
+		"short_url":     u.GetShortUrl(),
+		"long_url":      u.GetLongUrl(),
+		"user_id":       u.GetUserId(),
+		"active":        u.GetActive(),
+		"expires_at":    u.GetExpiresAt(),
+		"created_at":    u.GetCreatedAt(),
+		"updated_at":    u.GetUpdatedAt(),
+		"click_count":   u.GetClickCount(),
+		"last_accessed": u.GetLastAccessed(),
+		"tags":          u.GetTags(),
+	}
+}
+
+// parseGranularity maps the granularity query parameter to its proto enum
+func parseGranularity(value string) (analyticspb.Granularity, error) {
+	switch value {
+	case "":
+		return analyticspb.Granularity_GRANULARITY_UNSPECIFIED, nil
+	case "hour":
+		return analyticspb.Granularity_GRANULARITY_HOUR, nil
+	case "day":
+		return analyticspb.Granularity_GRANULARITY_DAY, nil
+	case "month":
+		return analyticspb.Granularity_GRANULARITY_MONTH, nil
+	default:
+		return 0, fmt.Errorf("granularity must be one of hour, day or month")
+	}
+}
+
+func timeSeriesJSON(series []*analyticspb.TimeBucket) []map[string]interface{} {
+	out := make([]map[string]interface{}, 0, len(series))
+	for _, b := range series {
+		out = append(out, map[string]interface{}{
+			"bucket_start":  b.GetBucketStart(),
+			"clicks":        b.GetClicks(),
+			"unique_clicks": b.GetUniqueClicks(),
+		})
+	}
+	return out
+}
+
+func countsJSON(entries []*analyticspb.CountEntry) []map[string]interface{} {
+	out := make([]map[string]interface{}, 0, len(entries))
+	for _, e := range entries {
+		out = append(out, map[string]interface{}{"value": e.GetValue(), "count": e.GetCount()})
+	}
+	return out
+}
+
+// httpStatusFromGRPC maps a gRPC error from a backend service to an HTTP status code
+func httpStatusFromGRPC(err error) int {
+	switch status.Code(err) {
+	case codes.InvalidArgument:
+		return http.StatusBadRequest
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.FailedPrecondition:
+		return http.StatusPreconditionFailed
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
 func main() {
+	var cfg Config
+	if args := config.MustLoad(&cfg, "api-gateway"); len(args) > 0 {
+		log.Fatalf("unexpected arguments %q", args)
+	}
+
 	// Set up gRPC connections
-	userConn, err := grpc.Dial(userServiceAddress, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	userConn, err := grpc.Dial(cfg.UserServiceAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
 	if err != nil {
 		log.Fatalf("did not connect to user service: %v", err)
 	}
 	defer userConn.Close()
 
-	shortenerConn, err := grpc.Dial(shortenerServiceAddress, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	shortenerConn, err := grpc.Dial(cfg.ShortenerServiceAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
 	if err != nil {
 		log.Fatalf("did not connect to shortener service: %v", err)
 	}
 	defer shortenerConn.Close()
 
-	apig := NewAPIGateway(userConn, shortenerConn)
+	analyticsConn, err := grpc.Dial(cfg.AnalyticsServiceAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		log.Fatalf("did not connect to analytics service: %v", err)
+	}
+	defer analyticsConn.Close()
+
+	apig := NewAPIGateway(userConn, shortenerConn, analyticsConn)
 
 	r := mux.NewRouter()
 
@@ -210,7 +687,16 @@ func main() {
 	// Authenticated routes - using individual middleware wrapping instead of subrouter
 	r.Handle("/auth/shorten", apig.AuthMiddleware(http.HandlerFunc(apig.ShortenURL))).Methods("POST")
 	r.Handle("/auth/update/{shortCode}", apig.AuthMiddleware(http.HandlerFunc(apig.UpdateURLDestination))).Methods("PUT")
-
-	log.Printf("API Gateway listening on :8080")
-	log.Fatal(http.ListenAndServe(":8080", r))
+	r.Handle("/auth/urls", apig.AuthMiddleware(http.HandlerFunc(apig.ListURLs))).Methods("GET")
+	r.Handle("/auth/urls/bulk", apig.AuthMiddleware(http.HandlerFunc(apig.BulkShortenURLs))).Methods("POST")
+	r.Handle("/auth/urls/{shortCode}", apig.AuthMiddleware(http.HandlerFunc(apig.GetURLInfo))).Methods("GET")
+	r.Handle("/auth/urls/{shortCode}", apig.AuthMiddleware(http.HandlerFunc(apig.DeleteURL))).Methods("DELETE")
+	r.Handle("/auth/urls/{shortCode}", apig.AuthMiddleware(http.HandlerFunc(apig.SetURLActive))).Methods("PATCH")
+	r.Handle("/auth/urls/{shortCode}/stats", apig.AuthMiddleware(http.HandlerFunc(apig.GetLinkStats))).Methods("GET")
+	r.Handle("/auth/stats/summary", apig.AuthMiddleware(http.HandlerFunc(apig.GetUserSummary))).Methods("GET")
+	r.Handle("/auth/urls/{shortCode}/revisions", apig.AuthMiddleware(http.HandlerFunc(apig.ListURLRevisions))).Methods("GET")
+	r.Handle("/auth/urls/{shortCode}/revisions/{revisionId}/restore", apig.AuthMiddleware(http.HandlerFunc(apig.RestoreURLRevision))).Methods("POST")
+
+	log.Printf("API Gateway listening on %s", cfg.ListenAddr)
+	log.Fatal(http.ListenAndServe(cfg.ListenAddr, r))
 }
@@ -0,0 +1,314 @@
+// Package config loads typed service settings from defaults, an optional
+// YAML file, environment variables and command-line flags.
+//
+// Settings are the fields of a struct, described by tags:
+//
+//	ListenAddr string `yaml:"listen_addr" env:"LISTEN_ADDR" flag:"listen" default:":8080" usage:"address to listen on"`
+//
+// Each source overrides the one before it: the default tag, the YAML file
+// named by -config or CONFIG_FILE, the env variable and finally the flag.
+// Fields tagged required:"true" must end up non-empty. Nested structs are
+// loaded the same way, and a struct implementing Validator is validated
+// once everything is loaded.
+//
+// Supported field types are string, bool, integers, time.Duration and
+// []string, which is written as a comma separated list in env variables,
+// flags and defaults.
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Validator is implemented by settings that check themselves after loading
+type Validator interface {
+	Validate() error
+}
+
+// field is a setting found in the config struct
+type field struct {
+	value    reflect.Value
+	name     string // Go path, for error messages
+	env      string
+	flag     string
+	def      string
+	usage    string
+	required bool
+}
+
+// flagValue records the raw value of a flag; it is parsed into its field
+// only if the flag is actually set
+type flagValue struct {
+	raw    string
+	isBool bool
+}
+
+func (v *flagValue) String() string     { return v.raw }
+func (v *flagValue) Set(s string) error { v.raw = s; return nil }
+func (v *flagValue) IsBoolFlag() bool   { return v.isBool }
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// Load fills cfg, a pointer to a struct, and returns the command-line
+// arguments left after the flags, e.g. a subcommand. name is the program
+// name used in the flag usage message.
+func Load(cfg any, name string, args []string) ([]string, error) {
+	v := reflect.ValueOf(cfg)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("config: %T is not a pointer to a struct", cfg)
+	}
+
+	fields, err := collect(v.Elem(), "")
+	if err != nil {
+		return nil, err
+	}
+
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	configFile := fs.String("config", os.Getenv("CONFIG_FILE"), "YAML config file (env CONFIG_FILE)")
+	flags := make(map[string]*flagValue)
+	for _, f := range fields {
+		if f.flag == "" {
+			continue
+		}
+		fv := &flagValue{isBool: f.value.Kind() == reflect.Bool}
+		flags[f.flag] = fv
+		usage := f.usage
+		if usage == "" {
+			usage = f.name
+		}
+		if f.env != "" {
+			usage += " (env " + f.env + ")"
+		}
+		fs.Var(fv, f.flag, usage)
+		if f.def != "" {
+			fs.Lookup(f.flag).DefValue = f.def
+		}
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	for _, f := range fields {
+		if f.def != "" {
+			if err := set(f.value, f.def); err != nil {
+				return nil, fmt.Errorf("config: default for %s: %v", f.name, err)
+			}
+		}
+	}
+
+	if *configFile != "" {
+		if err := loadFile(cfg, *configFile); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, f := range fields {
+		if f.env == "" {
+			continue
+		}
+		if raw, ok := os.LookupEnv(f.env); ok && raw != "" {
+			if err := set(f.value, raw); err != nil {
+				return nil, fmt.Errorf("invalid %s %q: %v", f.env, raw, err)
+			}
+		}
+	}
+
+	var flagErr error
+	fs.Visit(func(fl *flag.Flag) {
+		fv, ok := flags[fl.Name]
+		if !ok || flagErr != nil {
+			return
+		}
+		for _, f := range fields {
+			if f.flag == fl.Name {
+				if err := set(f.value, fv.raw); err != nil {
+					flagErr = fmt.Errorf("invalid -%s %q: %v", fl.Name, fv.raw, err)
+				}
+				return
+			}
+		}
+	})
+	if flagErr != nil {
+		return nil, flagErr
+	}
+
+	for _, f := range fields {
+		if f.required && f.value.IsZero() {
+			return nil, fmt.Errorf("%s is required", f.source())
+		}
+	}
+	if err := validate(v.Elem()); err != nil {
+		return nil, err
+	}
+
+	return fs.Args(), nil
+}
+
+// MustLoad is Load for os.Args that exits the program if the configuration
+// is invalid
+func MustLoad(cfg any, name string) []string {
+	args, err := Load(cfg, name, os.Args[1:])
+	switch {
+	case errors.Is(err, flag.ErrHelp):
+		os.Exit(0)
+	case err != nil:
+		fmt.Fprintf(os.Stderr, "%s: invalid configuration: %v\n", name, err)
+		os.Exit(2)
+	}
+	return args
+}
+
+// collect lists the settings in struct v, recursing into nested structs
+// that aren't settings themselves
+func collect(v reflect.Value, prefix string) ([]field, error) {
+	var fields []field
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		fv := v.Field(i)
+		name := prefix + sf.Name
+
+		env, hasEnv := sf.Tag.Lookup("env")
+		flagName, hasFlag := sf.Tag.Lookup("flag")
+		def, hasDef := sf.Tag.Lookup("default")
+		if fv.Kind() == reflect.Struct && !hasEnv && !hasFlag && !hasDef {
+			nested, err := collect(fv, name+".")
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, nested...)
+			continue
+		}
+
+		if !settable(fv.Type()) {
+			return nil, fmt.Errorf("config: %s has unsupported type %s", name, fv.Type())
+		}
+		fields = append(fields, field{
+			value:    fv,
+			name:     name,
+			env:      env,
+			flag:     flagName,
+			def:      def,
+			usage:    sf.Tag.Get("usage"),
+			required: sf.Tag.Get("required") == "true",
+		})
+	}
+	return fields, nil
+}
+
+func settable(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	case reflect.Slice:
+		return t.Elem().Kind() == reflect.String
+	default:
+		return false
+	}
+}
+
+// set parses raw into v according to its type
+func set(v reflect.Value, raw string) error {
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(raw, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Slice:
+		var items []string
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		v.Set(reflect.ValueOf(items))
+	}
+	return nil
+}
+
+// loadFile decodes the YAML file at path over cfg. Unknown keys are
+// rejected so a misspelt setting doesn't go unnoticed.
+func loadFile(cfg any, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("config: %v", err)
+	}
+	defer f.Close()
+
+	dec := yaml.NewDecoder(f)
+	dec.KnownFields(true)
+	if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("config: %s: %v", path, err)
+	}
+	return nil
+}
+
+// validate calls Validate on v and on every nested struct, innermost first
+func validate(v reflect.Value) error {
+	for i := 0; i < v.NumField(); i++ {
+		if fv := v.Field(i); fv.Kind() == reflect.Struct && v.Type().Field(i).IsExported() {
+			if err := validate(fv); err != nil {
+				return err
+			}
+		}
+	}
+	if val, ok := v.Addr().Interface().(Validator); ok {
+		return val.Validate()
+	}
+	return nil
+}
+
+// source names where a setting can be given, for error messages
+func (f field) source() string {
+	var sources []string
+	if f.env != "" {
+		sources = append(sources, f.env)
+	}
+	if f.flag != "" {
+		sources = append(sources, "-"+f.flag)
+	}
+	if len(sources) == 0 {
+		return f.name
+	}
+	return strings.Join(sources, " or ")
+}
@@ -0,0 +1,117 @@
+package db
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"log"
+	"path"
+
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/pressly/goose/v3"
+	"github.com/pressly/goose/v3/lock"
+)
+
+// The goose migrations, compiled into every service binary
+//
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// newMigrator returns a goose provider for the embedded migrations over the
+// DB pool. Every run holds a Postgres advisory lock, so replicas starting at
+// the same time apply the migrations one after another instead of racing.
+func newMigrator() (*goose.Provider, error) {
+	if DB == nil {
+		return nil, fmt.Errorf("database is not initialized")
+	}
+
+	fsys, err := fs.Sub(migrations, "migrations")
+	if err != nil {
+		return nil, err
+	}
+	locker, err := lock.NewPostgresSessionLocker()
+	if err != nil {
+		return nil, err
+	}
+
+	return goose.NewProvider(goose.DialectPostgres, stdlib.OpenDBFromPool(DB), fsys, goose.WithSessionLocker(locker))
+}
+
+// MigrateUp applies all pending migrations
+func MigrateUp(ctx context.Context) error {
+	p, err := newMigrator()
+	if err != nil {
+		return err
+	}
+	defer p.Close()
+
+	results, err := p.Up(ctx)
+	for _, r := range results {
+		log.Printf("Applied migration %s in %v", path.Base(r.Source.Path), r.Duration)
+	}
+	if err != nil {
+		return err
+	}
+	if len(results) == 0 {
+		log.Println("Database schema is up to date")
+	}
+	return nil
+}
+
+// MigrateDown rolls back the most recently applied migration
+func MigrateDown(ctx context.Context) error {
+	p, err := newMigrator()
+	if err != nil {
+		return err
+	}
+	defer p.Close()
+
+	r, err := p.Down(ctx)
+	if err != nil {
+		return err
+	}
+	log.Printf("Rolled back migration %s in %v", path.Base(r.Source.Path), r.Duration)
+	return nil
+}
+
+// MigrationStatus prints whether each migration has been applied
+func MigrationStatus(ctx context.Context) error {
+	p, err := newMigrator()
+	if err != nil {
+		return err
+	}
+	defer p.Close()
+
+	statuses, err := p.Status(ctx)
+	if err != nil {
+		return err
+	}
+	for _, s := range statuses {
+		applied := "Pending"
+		if s.State == goose.StateApplied {
+			applied = s.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		fmt.Printf("%-20s %s\n", applied, path.Base(s.Source.Path))
+	}
+	return nil
+}
+
+// RunMigrateCommand implements the "migrate up|down|status" subcommand
+func RunMigrateCommand(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: migrate up|down|status")
+	}
+
+	ctx := context.Background()
+	switch args[0] {
+	case "up":
+		return MigrateUp(ctx)
+	case "down":
+		return MigrateDown(ctx)
+	case "status":
+		return MigrationStatus(ctx)
+	default:
+		return fmt.Errorf("unknown migrate command %q, expected up, down or status", args[0])
+	}
+}
@@ -0,0 +1,16 @@
+-- +goose Up
+CREATE TABLE url_revisions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    url_id UUID NOT NULL REFERENCES urls(id) ON DELETE CASCADE,
+    short_code VARCHAR(20) NOT NULL,
+    previous_long_url TEXT NOT NULL,
+    new_long_url TEXT NOT NULL,
+    changed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    changed_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_url_revisions_url_id ON url_revisions(url_id, changed_at DESC);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_url_revisions_url_id;
+DROP TABLE url_revisions;
@@ -0,0 +1,18 @@
+-- +goose Up
+CREATE TABLE outbox (
+    id BIGSERIAL PRIMARY KEY,
+    topic VARCHAR(255) NOT NULL,
+    message_key TEXT,
+    payload BYTEA NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    sent_at TIMESTAMP WITH TIME ZONE
+);
+
+-- The relay only ever scans rows that have not been published yet
+CREATE INDEX idx_outbox_pending ON outbox(id) WHERE sent_at IS NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_outbox_pending;
+DROP TABLE outbox;
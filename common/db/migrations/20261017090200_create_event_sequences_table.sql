@@ -0,0 +1,9 @@
+-- +goose Up
+-- Gapless per-topic counters so consumers can detect missed events
+CREATE TABLE event_sequences (
+    topic VARCHAR(255) PRIMARY KEY,
+    value BIGINT NOT NULL
+);
+
+-- +goose Down
+DROP TABLE event_sequences;
@@ -0,0 +1,8 @@
+-- +goose Up
+ALTER TABLE analytics ADD COLUMN country VARCHAR(2);
+
+CREATE INDEX idx_analytics_short_code_timestamp ON analytics(short_code, timestamp);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_analytics_short_code_timestamp;
+ALTER TABLE analytics DROP COLUMN country;
@@ -0,0 +1,30 @@
+-- +goose Up
+-- Click counts pre-aggregated per short code and time bucket. Each bucket has
+-- one 'total' row plus one row per value of every dimension
+-- ('referer_domain', 'country', 'device_class').
+CREATE TABLE clicks_hourly (
+    short_code VARCHAR(20) NOT NULL,
+    bucket_start TIMESTAMP WITH TIME ZONE NOT NULL,
+    dimension VARCHAR(20) NOT NULL,
+    value TEXT NOT NULL,
+    clicks BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (short_code, bucket_start, dimension, value)
+);
+
+CREATE TABLE clicks_daily (
+    short_code VARCHAR(20) NOT NULL,
+    bucket_start TIMESTAMP WITH TIME ZONE NOT NULL,
+    dimension VARCHAR(20) NOT NULL,
+    value TEXT NOT NULL,
+    clicks BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (short_code, bucket_start, dimension, value)
+);
+
+CREATE INDEX idx_clicks_hourly_bucket_start ON clicks_hourly(bucket_start);
+CREATE INDEX idx_clicks_daily_bucket_start ON clicks_daily(bucket_start);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_clicks_daily_bucket_start;
+DROP INDEX IF EXISTS idx_clicks_hourly_bucket_start;
+DROP TABLE clicks_daily;
+DROP TABLE clicks_hourly;
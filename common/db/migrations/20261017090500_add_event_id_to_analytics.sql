@@ -0,0 +1,9 @@
+-- +goose Up
+-- Producer-assigned event ID; replayed or redelivered events are skipped on insert
+ALTER TABLE analytics ADD COLUMN event_id UUID;
+
+CREATE UNIQUE INDEX idx_analytics_event_id ON analytics(event_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_analytics_event_id;
+ALTER TABLE analytics DROP COLUMN event_id;
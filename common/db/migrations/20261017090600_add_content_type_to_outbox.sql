@@ -0,0 +1,7 @@
+-- +goose Up
+-- Encoding of payload, sent as the content-type Kafka header. NULL for rows
+-- written before protobuf events, which hold legacy JSON.
+ALTER TABLE outbox ADD COLUMN content_type VARCHAR(100);
+
+-- +goose Down
+ALTER TABLE outbox DROP COLUMN content_type;
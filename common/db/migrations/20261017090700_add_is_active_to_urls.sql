@@ -0,0 +1,6 @@
+-- +goose Up
+-- Deactivated links are kept but no longer redirect
+ALTER TABLE urls ADD COLUMN is_active BOOLEAN NOT NULL DEFAULT TRUE;
+
+-- +goose Down
+ALTER TABLE urls DROP COLUMN is_active;
@@ -0,0 +1,7 @@
+-- +goose Up
+-- Serves a user's link listing in the default newest-first order straight
+-- from the index; other sort keys fall back to idx_urls_user_id
+CREATE INDEX idx_urls_user_id_created_at ON urls(user_id, (COALESCE(created_at, 'epoch')), id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_urls_user_id_created_at;
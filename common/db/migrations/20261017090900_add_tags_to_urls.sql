@@ -0,0 +1,6 @@
+-- +goose Up
+-- Free-form labels for organising links, e.g. by campaign
+ALTER TABLE urls ADD COLUMN tags TEXT[] NOT NULL DEFAULT '{}';
+
+-- +goose Down
+ALTER TABLE urls DROP COLUMN tags;
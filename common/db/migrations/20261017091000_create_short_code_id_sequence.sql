@@ -0,0 +1,8 @@
+-- +goose Up
+-- Each nextval reserves a range of 1000 IDs for one shortener-service
+-- instance, which turns them into short codes without further queries. The
+-- increment must match repository.CodeRangeSize.
+CREATE SEQUENCE short_code_ids INCREMENT BY 1000 MINVALUE 0 START WITH 0;
+
+-- +goose Down
+DROP SEQUENCE short_code_ids;
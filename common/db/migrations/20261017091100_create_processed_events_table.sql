@@ -0,0 +1,14 @@
+-- +goose Up
+-- IDs of the click events already added to urls.click_count, recorded in the
+-- same transaction as the increment so redelivered or replayed events are
+-- counted once. Rows are pruned once redelivery is no longer expected.
+CREATE TABLE processed_events (
+    event_id UUID PRIMARY KEY,
+    processed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_processed_events_processed_at ON processed_events(processed_at);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_processed_events_processed_at;
+DROP TABLE processed_events;
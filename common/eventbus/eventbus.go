@@ -0,0 +1,38 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+)
+
+var ErrClosed = errors.New("eventbus: closed")
+
+// Message is one event on the bus. Topic, Key, Value and Headers are set by
+// the publisher; Partition and Offset are filled in by the subscriber that
+// returned the message and are ignored when publishing.
+type Message struct {
+	Topic     string
+	Key       []byte
+	Value     []byte
+	Headers   map[string]string
+	Partition int
+	Offset    int64
+}
+
+// Publisher writes messages to their topics. Messages with the same key go
+// to the same partition, so their order is preserved.
+type Publisher interface {
+	// Publish returns once every message has been accepted by the bus
+	Publish(ctx context.Context, msgs ...Message) error
+	Close() error
+}
+
+// Subscriber reads one topic as a member of a consumer group. Each group sees
+// every message; subscribers in the same group share them.
+type Subscriber interface {
+	// Fetch blocks until the next message arrives or ctx is done
+	Fetch(ctx context.Context) (Message, error)
+	// Commit marks messages as processed by the group
+	Commit(ctx context.Context, msgs ...Message) error
+	Close() error
+}
@@ -0,0 +1,154 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// KafkaPublisher publishes to Kafka. Messages are hashed by key onto
+// partitions and every in-sync replica must acknowledge a write.
+type KafkaPublisher struct {
+	writer *kafka.Writer
+}
+
+func NewKafkaPublisher(brokers ...string) *KafkaPublisher {
+	return &KafkaPublisher{
+		writer: &kafka.Writer{
+			Addr:         kafka.TCP(brokers...),
+			Balancer:     &kafka.Hash{},
+			RequiredAcks: kafka.RequireAll,
+		},
+	}
+}
+
+func (p *KafkaPublisher) Publish(ctx context.Context, msgs ...Message) error {
+	kmsgs := make([]kafka.Message, len(msgs))
+	for i, msg := range msgs {
+		kmsgs[i] = kafka.Message{Topic: msg.Topic, Key: msg.Key, Value: msg.Value}
+		for k, v := range msg.Headers {
+			kmsgs[i].Headers = append(kmsgs[i].Headers, kafka.Header{Key: k, Value: []byte(v)})
+		}
+	}
+	return p.writer.WriteMessages(ctx, kmsgs...)
+}
+
+func (p *KafkaPublisher) Close() error {
+	return p.writer.Close()
+}
+
+// EnsureSingleTopicPartition creates topics that don't exist yet with one
+// partition, using the brokers' default replication factor, and fails if
+// any of them has more than one. Publishers hash messages onto partitions,
+// so only a single-partition topic keeps all of its messages in one order.
+func EnsureSingleTopicPartition(ctx context.Context, brokers []string, topics ...string) error {
+	client := &kafka.Client{Addr: kafka.TCP(brokers...)}
+
+	configs := make([]kafka.TopicConfig, len(topics))
+	for i, topic := range topics {
+		configs[i] = kafka.TopicConfig{Topic: topic, NumPartitions: 1, ReplicationFactor: -1}
+	}
+	created, err := client.CreateTopics(ctx, &kafka.CreateTopicsRequest{Topics: configs})
+	if err != nil {
+		return err
+	}
+	for topic, err := range created.Errors {
+		if err != nil && !errors.Is(err, kafka.TopicAlreadyExists) {
+			return fmt.Errorf("failed to create topic %s: %w", topic, err)
+		}
+	}
+
+	meta, err := client.Metadata(ctx, &kafka.MetadataRequest{Topics: topics})
+	if err != nil {
+		return err
+	}
+	for _, t := range meta.Topics {
+		if t.Error != nil {
+			return fmt.Errorf("failed to describe topic %s: %w", t.Name, t.Error)
+		}
+		if len(t.Partitions) != 1 {
+			return fmt.Errorf("topic %s has %d partitions, want 1", t.Name, len(t.Partitions))
+		}
+	}
+	return nil
+}
+
+type KafkaSubscriberConfig struct {
+	Brokers []string
+	Topic   string
+	GroupID string
+
+	// Optional; kafka-go's defaults apply when zero
+	MinBytes         int
+	MaxBytes         int
+	MaxWait          time.Duration
+	RebalanceTimeout time.Duration
+}
+
+// KafkaSubscriber reads a topic as part of a Kafka consumer group.
+// Partitions are spread across the group's members and rebalanced as members
+// join and leave. Offsets are only committed by Commit.
+type KafkaSubscriber struct {
+	reader *kafka.Reader
+}
+
+func NewKafkaSubscriber(cfg KafkaSubscriberConfig) *KafkaSubscriber {
+	return &KafkaSubscriber{
+		reader: kafka.NewReader(kafka.ReaderConfig{
+			Brokers:  cfg.Brokers,
+			Topic:    cfg.Topic,
+			GroupID:  cfg.GroupID,
+			MinBytes: cfg.MinBytes,
+			MaxBytes: cfg.MaxBytes,
+			MaxWait:  cfg.MaxWait,
+			GroupBalancers: []kafka.GroupBalancer{
+				kafka.RangeGroupBalancer{},
+				kafka.RoundRobinGroupBalancer{},
+			},
+			RebalanceTimeout: cfg.RebalanceTimeout,
+			Dialer: &kafka.Dialer{
+				Timeout:   10 * time.Second,
+				DualStack: true,
+			},
+		}),
+	}
+}
+
+func (s *KafkaSubscriber) Fetch(ctx context.Context) (Message, error) {
+	kmsg, err := s.reader.FetchMessage(ctx)
+	if err != nil {
+		return Message{}, err
+	}
+
+	msg := Message{
+		Topic:     kmsg.Topic,
+		Key:       kmsg.Key,
+		Value:     kmsg.Value,
+		Partition: kmsg.Partition,
+		Offset:    kmsg.Offset,
+	}
+	if len(kmsg.Headers) > 0 {
+		msg.Headers = make(map[string]string, len(kmsg.Headers))
+		for _, h := range kmsg.Headers {
+			msg.Headers[h.Key] = string(h.Value)
+		}
+	}
+	return msg, nil
+}
+
+func (s *KafkaSubscriber) Commit(ctx context.Context, msgs ...Message) error {
+	kmsgs := make([]kafka.Message, len(msgs))
+	for i, msg := range msgs {
+		kmsgs[i] = kafka.Message{Topic: msg.Topic, Partition: msg.Partition, Offset: msg.Offset}
+	}
+	return s.reader.CommitMessages(ctx, kmsgs...)
+}
+
+// Close leaves the consumer group, so the remaining members pick up this
+// subscriber's partitions straight away
+func (s *KafkaSubscriber) Close() error {
+	return s.reader.Close()
+}
@@ -0,0 +1,155 @@
+package eventbus
+
+import (
+	"context"
+	"sync"
+)
+
+// Memory is an in-process bus for local development and tests. Each topic
+// is a single partition kept in memory for the life of the bus. Like Kafka,
+// every consumer group reads the whole topic, subscribers in one group share
+// its messages, and messages that were fetched but never committed are
+// delivered again once the group's last subscriber closes.
+type Memory struct {
+	mu     sync.Mutex
+	topics map[string]*memoryTopic
+}
+
+type memoryTopic struct {
+	log    []Message
+	groups map[string]*memoryGroup
+	// closed and replaced whenever a message is appended
+	appended chan struct{}
+}
+
+type memoryGroup struct {
+	next      int64 // offset of the next message to fetch
+	committed int64 // offset of the first uncommitted message
+	members   int
+}
+
+func NewMemory() *Memory {
+	return &Memory{topics: make(map[string]*memoryTopic)}
+}
+
+// topic returns the named topic, creating it if needed. m.mu must be held.
+func (m *Memory) topic(name string) *memoryTopic {
+	t, ok := m.topics[name]
+	if !ok {
+		t = &memoryTopic{groups: make(map[string]*memoryGroup), appended: make(chan struct{})}
+		m.topics[name] = t
+	}
+	return t
+}
+
+// Publisher returns a Publisher writing to this bus
+func (m *Memory) Publisher() Publisher {
+	return memoryPublisher{bus: m}
+}
+
+// Subscriber returns a Subscriber reading topic as a member of groupID
+func (m *Memory) Subscriber(topic, groupID string) Subscriber {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	t := m.topic(topic)
+	g, ok := t.groups[groupID]
+	if !ok {
+		g = &memoryGroup{}
+		t.groups[groupID] = g
+	}
+	g.members++
+	return &memorySubscriber{bus: m, topic: topic, group: g, closed: make(chan struct{})}
+}
+
+type memoryPublisher struct {
+	bus *Memory
+}
+
+func (p memoryPublisher) Publish(ctx context.Context, msgs ...Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	p.bus.mu.Lock()
+	defer p.bus.mu.Unlock()
+
+	for _, msg := range msgs {
+		t := p.bus.topic(msg.Topic)
+		msg.Partition = 0
+		msg.Offset = int64(len(t.log))
+		t.log = append(t.log, msg)
+
+		close(t.appended)
+		t.appended = make(chan struct{})
+	}
+	return nil
+}
+
+func (p memoryPublisher) Close() error {
+	return nil
+}
+
+type memorySubscriber struct {
+	bus       *Memory
+	topic     string
+	group     *memoryGroup
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+func (s *memorySubscriber) Fetch(ctx context.Context) (Message, error) {
+	for {
+		s.bus.mu.Lock()
+		select {
+		case <-s.closed:
+			s.bus.mu.Unlock()
+			return Message{}, ErrClosed
+		default:
+		}
+
+		t := s.bus.topic(s.topic)
+		if s.group.next < int64(len(t.log)) {
+			msg := t.log[s.group.next]
+			s.group.next++
+			s.bus.mu.Unlock()
+			return msg, nil
+		}
+		appended := t.appended
+		s.bus.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return Message{}, ctx.Err()
+		case <-s.closed:
+			return Message{}, ErrClosed
+		case <-appended:
+		}
+	}
+}
+
+func (s *memorySubscriber) Commit(ctx context.Context, msgs ...Message) error {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+
+	for _, msg := range msgs {
+		if msg.Offset >= s.group.committed {
+			s.group.committed = msg.Offset + 1
+		}
+	}
+	return nil
+}
+
+func (s *memorySubscriber) Close() error {
+	s.closeOnce.Do(func() {
+		close(s.closed)
+
+		s.bus.mu.Lock()
+		defer s.bus.mu.Unlock()
+		s.group.members--
+		if s.group.members == 0 {
+			s.group.next = s.group.committed
+		}
+	})
+	return nil
+}
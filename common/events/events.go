@@ -0,0 +1,220 @@
+package events
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	eventspb "github.com/Farhang-Osman/url-shortener-project/pkg/proto/eventspb"
+)
+
+// Event types carried in EventEnvelope.event_type
+const (
+	TypeURLCreated = "url_created"
+	TypeURLClicked = "url_clicked"
+	TypeURLUpdated = "url_updated"
+	TypeURLDeleted = "url_deleted"
+)
+
+// Version is the schema version of the payloads this package produces and
+// the newest one it can decode
+const Version = 1
+
+// HeaderContentType is the Kafka header naming the encoding of a message.
+// Messages without it are sniffed so legacy JSON payloads keep working.
+const (
+	HeaderContentType   = "content-type"
+	ContentTypeProtobuf = "application/x-protobuf"
+	ContentTypeJSON     = "application/json"
+)
+
+var ErrUnsupportedVersion = errors.New("unsupported event version")
+
+// NewID returns a random (version 4) UUID identifying one event. Consumers
+// use it to drop redelivered copies.
+func NewID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	b[6] = (b[6] & 0x0f) | 0x40 // version 4
+	b[8] = (b[8] & 0x3f) | 0x80 // RFC 4122 variant
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+func newEnvelope(eventType string) *eventspb.EventEnvelope {
+	return &eventspb.EventEnvelope{
+		EventId:   NewID(),
+		EventType: eventType,
+		Version:   Version,
+		Timestamp: timestamppb.Now(),
+	}
+}
+
+func NewURLCreated(e *eventspb.URLCreatedV1) *eventspb.EventEnvelope {
+	env := newEnvelope(TypeURLCreated)
+	env.Payload = &eventspb.EventEnvelope_UrlCreated{UrlCreated: e}
+	return env
+}
+
+func NewURLClicked(e *eventspb.URLClickedV1) *eventspb.EventEnvelope {
+	env := newEnvelope(TypeURLClicked)
+	env.Payload = &eventspb.EventEnvelope_UrlClicked{UrlClicked: e}
+	return env
+}
+
+func NewURLUpdated(e *eventspb.URLUpdatedV1) *eventspb.EventEnvelope {
+	env := newEnvelope(TypeURLUpdated)
+	env.Payload = &eventspb.EventEnvelope_UrlUpdated{UrlUpdated: e}
+	return env
+}
+
+func NewURLDeleted(e *eventspb.URLDeletedV1) *eventspb.EventEnvelope {
+	env := newEnvelope(TypeURLDeleted)
+	env.Payload = &eventspb.EventEnvelope_UrlDeleted{UrlDeleted: e}
+	return env
+}
+
+// Marshal encodes an envelope for publishing with ContentTypeProtobuf
+func Marshal(env *eventspb.EventEnvelope) ([]byte, error) {
+	return proto.Marshal(env)
+}
+
+// Decode parses a message value into an envelope. contentType is the value
+// of the HeaderContentType header, if any. Payloads that are not protobuf
+// are decoded as the legacy JSON event named by legacyType.
+func Decode(value []byte, contentType, legacyType string) (*eventspb.EventEnvelope, error) {
+	if contentType == ContentTypeJSON || (contentType == "" && looksLikeJSON(value)) {
+		return decodeLegacyJSON(value, legacyType)
+	}
+
+	var env eventspb.EventEnvelope
+	if err := proto.Unmarshal(value, &env); err != nil {
+		return nil, err
+	}
+	if env.GetVersion() == 0 || env.GetVersion() > Version {
+		return nil, fmt.Errorf("%w: %s v%d", ErrUnsupportedVersion, env.GetEventType(), env.GetVersion())
+	}
+	if env.GetPayload() == nil {
+		return nil, fmt.Errorf("%s event %s has no payload", env.GetEventType(), env.GetEventId())
+	}
+	return &env, nil
+}
+
+func looksLikeJSON(value []byte) bool {
+	for _, c := range value {
+		switch c {
+		case ' ', '\t', '\r', '\n':
+			continue
+		case '{':
+			return true
+		default:
+			return false
+		}
+	}
+	return false
+}
+
+// Legacy JSON events, as published before the protobuf schemas existed
+
+type legacyURLCreated struct {
+	EventID   string    `json:"event_id"`
+	ShortCode string    `json:"short_code"`
+	LongURL   string    `json:"long_url"`
+	UserID    string    `json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type legacyURLClicked struct {
+	EventID   string    `json:"event_id"`
+	ShortCode string    `json:"short_code"`
+	ClickedAt time.Time `json:"clicked_at"`
+	UserAgent string    `json:"user_agent"`
+	Referer   string    `json:"referer"`
+	IPAddress string    `json:"ip_address"`
+	Country   string    `json:"country"`
+}
+
+type legacyURLUpdated struct {
+	EventID   string    `json:"event_id"`
+	ShortCode string    `json:"short_code"`
+	LongURL   string    `json:"long_url"`
+	UserID    string    `json:"user_id"`
+	Sequence  int64     `json:"sequence"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type legacyURLDeleted struct {
+	EventID   string    `json:"event_id"`
+	ShortCode string    `json:"short_code"`
+	UserID    string    `json:"user_id"`
+	Sequence  int64     `json:"sequence"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+// decodeLegacyJSON converts a legacy JSON event into an envelope. The event
+// ID is kept if the producer set one and left empty otherwise.
+func decodeLegacyJSON(value []byte, legacyType string) (*eventspb.EventEnvelope, error) {
+	env := &eventspb.EventEnvelope{EventType: legacyType, Version: Version}
+
+	switch legacyType {
+	case TypeURLCreated:
+		var e legacyURLCreated
+		if err := json.Unmarshal(value, &e); err != nil {
+			return nil, err
+		}
+		env.EventId, env.Timestamp = e.EventID, timestamppb.New(e.CreatedAt)
+		env.Payload = &eventspb.EventEnvelope_UrlCreated{UrlCreated: &eventspb.URLCreatedV1{
+			ShortCode: e.ShortCode,
+			LongUrl:   e.LongURL,
+			UserId:    e.UserID,
+			CreatedAt: timestamppb.New(e.CreatedAt),
+		}}
+	case TypeURLClicked:
+		var e legacyURLClicked
+		if err := json.Unmarshal(value, &e); err != nil {
+			return nil, err
+		}
+		env.EventId, env.Timestamp = e.EventID, timestamppb.New(e.ClickedAt)
+		env.Payload = &eventspb.EventEnvelope_UrlClicked{UrlClicked: &eventspb.URLClickedV1{
+			ShortCode: e.ShortCode,
+			ClickedAt: timestamppb.New(e.ClickedAt),
+			UserAgent: e.UserAgent,
+			Referer:   e.Referer,
+			IpAddress: e.IPAddress,
+			Country:   e.Country,
+		}}
+	case TypeURLUpdated:
+		var e legacyURLUpdated
+		if err := json.Unmarshal(value, &e); err != nil {
+			return nil, err
+		}
+		env.EventId, env.Timestamp = e.EventID, timestamppb.New(e.UpdatedAt)
+		env.Payload = &eventspb.EventEnvelope_UrlUpdated{UrlUpdated: &eventspb.URLUpdatedV1{
+			ShortCode: e.ShortCode,
+			LongUrl:   e.LongURL,
+			UserId:    e.UserID,
+			Sequence:  e.Sequence,
+			UpdatedAt: timestamppb.New(e.UpdatedAt),
+		}}
+	case TypeURLDeleted:
+		var e legacyURLDeleted
+		if err := json.Unmarshal(value, &e); err != nil {
+			return nil, err
+		}
+		env.EventId, env.Timestamp = e.EventID, timestamppb.New(e.DeletedAt)
+		env.Payload = &eventspb.EventEnvelope_UrlDeleted{UrlDeleted: &eventspb.URLDeletedV1{
+			ShortCode: e.ShortCode,
+			UserId:    e.UserID,
+			Sequence:  e.Sequence,
+			DeletedAt: timestamppb.New(e.DeletedAt),
+		}}
+	default:
+		return nil, fmt.Errorf("unknown legacy event type %q", legacyType)
+	}
+
+	return env, nil
+}
@@ -0,0 +1,73 @@
+package outbox
+
+import (
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/Farhang-Osman/url-shortener-project/common/events"
+	"github.com/Farhang-Osman/url-shortener-project/common/repository"
+	eventspb "github.com/Farhang-Osman/url-shortener-project/pkg/proto/eventspb"
+)
+
+// Topics of the URL events. The invalidation topics, updated and deleted,
+// must have a single partition so that every redirect-service replica sees
+// one ordered, gapless sequence per topic; both services check this with
+// eventbus.EnsureSingleTopicPartition at startup.
+const (
+	TopicURLCreated = "url-created-events"
+	TopicURLUpdated = "url-updated-events"
+	TopicURLDeleted = "url-deleted-events"
+)
+
+// newEvent wraps an envelope for the outbox, keyed by short code. Sequenced
+// events get their sequence number filled in by build when they are queued.
+func newEvent(topic, shortCode string, sequenced bool, build func(seq int64) *eventspb.EventEnvelope) repository.Event {
+	return repository.Event{
+		Topic:       topic,
+		Key:         shortCode,
+		ContentType: events.ContentTypeProtobuf,
+		Sequenced:   sequenced,
+		Payload: func(seq int64) ([]byte, error) {
+			return events.Marshal(build(seq))
+		},
+	}
+}
+
+// URLCreated is queued with a new URL
+func URLCreated(shortCode, longURL, userID string, createdAt time.Time) repository.Event {
+	return newEvent(TopicURLCreated, shortCode, false, func(int64) *eventspb.EventEnvelope {
+		return events.NewURLCreated(&eventspb.URLCreatedV1{
+			ShortCode: shortCode,
+			LongUrl:   longURL,
+			UserId:    userID,
+			CreatedAt: timestamppb.New(createdAt),
+		})
+	})
+}
+
+// URLUpdated is queued when a URL's destination or active state changes
+func URLUpdated(u *repository.URL, userID string) repository.Event {
+	return newEvent(TopicURLUpdated, u.ShortCode, true, func(seq int64) *eventspb.EventEnvelope {
+		return events.NewURLUpdated(&eventspb.URLUpdatedV1{
+			ShortCode: u.ShortCode,
+			LongUrl:   u.LongURL,
+			UserId:    userID,
+			Sequence:  seq,
+			UpdatedAt: timestamppb.Now(),
+			Disabled:  !u.Active,
+		})
+	})
+}
+
+// URLDeleted is queued when a URL is deleted
+func URLDeleted(shortCode, userID string) repository.Event {
+	return newEvent(TopicURLDeleted, shortCode, true, func(seq int64) *eventspb.EventEnvelope {
+		return events.NewURLDeleted(&eventspb.URLDeletedV1{
+			ShortCode: shortCode,
+			UserId:    userID,
+			Sequence:  seq,
+			DeletedAt: timestamppb.Now(),
+		})
+	})
+}
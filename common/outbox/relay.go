@@ -0,0 +1,120 @@
+// Package outbox relays events queued in the outbox table to the event bus,
+// and builds the events URL writes queue there.
+package outbox
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/Farhang-Osman/url-shortener-project/common/eventbus"
+	"github.com/Farhang-Osman/url-shortener-project/common/events"
+	"github.com/Farhang-Osman/url-shortener-project/common/repository"
+)
+
+const (
+	outboxBatchSize     = 100
+	outboxPollInterval  = 500 * time.Millisecond
+	outboxMaxBackoff    = 30 * time.Second
+	outboxRetention     = 7 * 24 * time.Hour
+	outboxPruneInterval = time.Hour
+)
+
+// Relay publishes events queued in the outbox to the event bus and marks
+// them sent. Events are only marked after the bus accepts the write, so
+// delivery is at-least-once. Several replicas can run a relay at the same
+// time: the outbox hands each event to one of them.
+type Relay struct {
+	outbox    repository.OutboxRepository
+	publisher eventbus.Publisher
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+}
+
+// NewRelay starts relaying the events queued in outbox to publisher
+func NewRelay(outbox repository.OutboxRepository, publisher eventbus.Publisher) *Relay {
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &Relay{
+		outbox:    outbox,
+		publisher: publisher,
+		cancel:    cancel,
+	}
+	r.wg.Add(1)
+	go r.run(ctx)
+	return r
+}
+
+// Close stops the relay. Unpublished events stay in the outbox for the next start.
+func (r *Relay) Close() {
+	r.cancel()
+	r.wg.Wait()
+}
+
+func (r *Relay) run(ctx context.Context) {
+	defer r.wg.Done()
+
+	backoff := outboxPollInterval
+	lastPrune := time.Time{}
+	for {
+		sent, err := r.publishBatch(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		switch {
+		case err != nil:
+			log.Printf("Warning: outbox relay failed, retrying in %v: %v", backoff, err)
+			backoff = min(backoff*2, outboxMaxBackoff)
+		case sent == outboxBatchSize:
+			// More rows are probably waiting, go again straight away
+			backoff = outboxPollInterval
+			continue
+		default:
+			backoff = outboxPollInterval
+		}
+
+		if time.Since(lastPrune) > outboxPruneInterval {
+			r.prune(ctx)
+			lastPrune = time.Now()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+	}
+}
+
+// publishBatch publishes up to outboxBatchSize pending events and returns
+// the number published
+func (r *Relay) publishBatch(ctx context.Context) (int, error) {
+	sent, err := r.outbox.PublishPending(ctx, outboxBatchSize, func(pending []repository.OutboxMessage) error {
+		messages := make([]eventbus.Message, len(pending))
+		for i, p := range pending {
+			messages[i] = eventbus.Message{Topic: p.Topic, Key: []byte(p.Key), Value: p.Payload}
+			// Events queued before content_type existed hold legacy JSON and go out without the header
+			if p.ContentType != "" {
+				messages[i].Headers = map[string]string{events.HeaderContentType: p.ContentType}
+			}
+		}
+		return r.publisher.Publish(ctx, messages...)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if sent > 0 {
+		log.Printf("Published %d outbox events", sent)
+	}
+	return sent, nil
+}
+
+// prune removes events that were published longer ago than outboxRetention
+func (r *Relay) prune(ctx context.Context) {
+	err := r.outbox.PruneSent(ctx, time.Now().Add(-outboxRetention))
+	if err != nil && ctx.Err() == nil {
+		log.Printf("Warning: failed to prune outbox: %v", err)
+	}
+}
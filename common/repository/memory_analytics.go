@@ -0,0 +1,256 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemoryAnalyticsRepository keeps analytics events in memory for tests and
+// local development. Statistics are computed from the raw events, so it
+// keeps no rollups, but ranges and top-N values match what the rollups
+// give. It is safe for concurrent use.
+type MemoryAnalyticsRepository struct {
+	mu      sync.Mutex
+	created []CreatedEvent
+	clicks  []ClickEvent
+	seen    map[string]bool // stored event IDs
+}
+
+func NewMemoryAnalyticsRepository() *MemoryAnalyticsRepository {
+	return &MemoryAnalyticsRepository{seen: make(map[string]bool)}
+}
+
+// isNew reports whether an event ID hasn't been stored yet and marks it
+// stored. Events without an ID are never deduplicated. r.mu must be held.
+func (r *MemoryAnalyticsRepository) isNew(eventID string) bool {
+	if eventID == "" {
+		return true
+	}
+	if r.seen[eventID] {
+		return false
+	}
+	r.seen[eventID] = true
+	return true
+}
+
+func (r *MemoryAnalyticsRepository) InsertCreated(ctx context.Context, events []CreatedEvent) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	inserted := 0
+	for _, e := range events {
+		if r.isNew(e.EventID) {
+			r.created = append(r.created, e)
+			inserted++
+		}
+	}
+	return inserted, nil
+}
+
+func (r *MemoryAnalyticsRepository) InsertClicks(ctx context.Context, events []ClickEvent) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	inserted := 0
+	for _, e := range events {
+		if r.isNew(e.EventID) {
+			r.clicks = append(r.clicks, e)
+			inserted++
+		}
+	}
+	return inserted, nil
+}
+
+// latestCreated returns the newest url_created event for shortCode. r.mu
+// must be held.
+func (r *MemoryAnalyticsRepository) latestCreated(shortCode string) (CreatedEvent, bool) {
+	var latest CreatedEvent
+	found := false
+	for _, e := range r.created {
+		if e.ShortCode == shortCode && (!found || e.CreatedAt.After(latest.CreatedAt)) {
+			latest, found = e, true
+		}
+	}
+	return latest, found
+}
+
+func (r *MemoryAnalyticsRepository) LinkOwner(ctx context.Context, shortCode string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e, ok := r.latestCreated(shortCode)
+	if !ok {
+		return "", ErrNotFound
+	}
+	return e.UserID, nil
+}
+
+// clicksIn returns the clicks on the short codes accepted by match that fall
+// in q's range. r.mu must be held.
+func (r *MemoryAnalyticsRepository) clicksIn(q StatsQuery, match func(shortCode string) bool) []ClickEvent {
+	var clicks []ClickEvent
+	for _, c := range r.clicks {
+		if match(c.ShortCode) && !c.ClickedAt.Before(q.From) && c.ClickedAt.Before(q.To) {
+			clicks = append(clicks, c)
+		}
+	}
+	return clicks
+}
+
+// countClicks returns the number of clicks and of distinct client IPs.
+// Clicks without an IP are not counted as unique.
+func countClicks(clicks []ClickEvent) (int64, int64) {
+	ips := make(map[string]bool)
+	for _, c := range clicks {
+		if c.IPAddress != "" {
+			ips[c.IPAddress] = true
+		}
+	}
+	return int64(len(clicks)), int64(len(ips))
+}
+
+func bucketStart(t time.Time, granularity string) time.Time {
+	t = t.UTC()
+	switch truncUnit(granularity) {
+	case GranularityHour:
+		return t.Truncate(time.Hour)
+	case GranularityMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	}
+}
+
+func timeSeries(clicks []ClickEvent, granularity string) []TimeBucket {
+	buckets := make(map[time.Time][]ClickEvent)
+	for _, c := range clicks {
+		start := bucketStart(c.ClickedAt, granularity)
+		buckets[start] = append(buckets[start], c)
+	}
+
+	series := make([]TimeBucket, 0, len(buckets))
+	for start, inBucket := range buckets {
+		b := TimeBucket{Start: start}
+		b.Clicks, b.UniqueClicks = countClicks(inBucket)
+		series = append(series, b)
+	}
+	sort.Slice(series, func(i, j int) bool { return series[i].Start.Before(series[j].Start) })
+	return series
+}
+
+// topCounts counts clicks by value, most clicked first
+func topCounts(clicks []ClickEvent, value func(c ClickEvent) string, limit int) []CountEntry {
+	counts := make(map[string]int64)
+	for _, c := range clicks {
+		counts[value(c)]++
+	}
+
+	entries := make([]CountEntry, 0, len(counts))
+	for v, n := range counts {
+		entries = append(entries, CountEntry{Value: v, Count: n})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Count != entries[j].Count {
+			return entries[i].Count > entries[j].Count
+		}
+		return entries[i].Value < entries[j].Value
+	})
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries
+}
+
+func (r *MemoryAnalyticsRepository) LinkStats(ctx context.Context, shortCode string, q StatsQuery) (*LinkStats, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, q.From, q.To = rollupRange(q)
+	clicks := r.clicksIn(q, func(code string) bool { return code == shortCode })
+
+	stats := &LinkStats{Series: timeSeries(clicks, q.Granularity)}
+	stats.TotalClicks, stats.UniqueClicks = countClicks(clicks)
+	stats.TopReferers = topCounts(clicks, func(c ClickEvent) string { return rawValue(c.Referer) }, q.TopLimit)
+	stats.TopUserAgents = topCounts(clicks, func(c ClickEvent) string { return rawValue(c.UserAgent) }, q.TopLimit)
+	stats.TopCountries = topCounts(clicks, func(c ClickEvent) string { return countryValue(c.Country) }, q.TopLimit)
+	stats.TopRefererDomains = topCounts(clicks, func(c ClickEvent) string { return refererDomain(c.Referer) }, q.TopLimit)
+	stats.TopDeviceClasses = topCounts(clicks, func(c ClickEvent) string { return deviceClass(c.UserAgent) }, q.TopLimit)
+	return stats, nil
+}
+
+func (r *MemoryAnalyticsRepository) UserSummary(ctx context.Context, userID string, q StatsQuery) (*UserSummary, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	links := make(map[string]bool)
+	for _, e := range r.created {
+		if e.UserID == userID {
+			links[e.ShortCode] = true
+		}
+	}
+	_, q.From, q.To = rollupRange(q)
+	clicks := r.clicksIn(q, func(code string) bool { return links[code] })
+
+	summary := &UserSummary{TotalLinks: int64(len(links)), Series: timeSeries(clicks, q.Granularity)}
+	summary.TotalClicks, summary.UniqueClicks = countClicks(clicks)
+
+	byLink := make(map[string][]ClickEvent)
+	for _, c := range clicks {
+		byLink[c.ShortCode] = append(byLink[c.ShortCode], c)
+	}
+	for shortCode, linkClicks := range byLink {
+		l := LinkClicks{ShortCode: shortCode}
+		if e, ok := r.latestCreated(shortCode); ok {
+			l.LongURL = e.LongURL
+		}
+		l.Clicks, l.UniqueClicks = countClicks(linkClicks)
+		summary.TopLinks = append(summary.TopLinks, l)
+	}
+	sort.Slice(summary.TopLinks, func(i, j int) bool {
+		a, b := summary.TopLinks[i], summary.TopLinks[j]
+		if a.Clicks != b.Clicks {
+			return a.Clicks > b.Clicks
+		}
+		return a.ShortCode < b.ShortCode
+	})
+	if len(summary.TopLinks) > q.TopLimit {
+		summary.TopLinks = summary.TopLinks[:q.TopLimit]
+	}
+	return summary, nil
+}
+
+func (r *MemoryAnalyticsRepository) DeleteLink(ctx context.Context, shortCode string, deletedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	created := r.created[:0]
+	for _, e := range r.created {
+		if e.ShortCode != shortCode || e.CreatedAt.After(deletedAt) {
+			created = append(created, e)
+		}
+	}
+	r.created = created
+
+	clicks := r.clicks[:0]
+	for _, c := range r.clicks {
+		if c.ShortCode != shortCode || c.ClickedAt.After(deletedAt) {
+			clicks = append(clicks, c)
+		}
+	}
+	r.clicks = clicks
+	return nil
+}
+
+// RebuildRollups has nothing to rebuild; it only reports the range and the
+// number of click events in it
+func (r *MemoryAnalyticsRepository) RebuildRollups(ctx context.Context, from, to time.Time) (time.Time, time.Time, int64, error) {
+	from, to = wholeDays(from, to)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	clicks := r.clicksIn(StatsQuery{From: from, To: to}, func(code string) bool { return code != "" })
+	return from, to, int64(len(clicks)), nil
+}
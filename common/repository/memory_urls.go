@@ -0,0 +1,394 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Farhang-Osman/url-shortener-project/common/events"
+)
+
+// MemoryURLRepository keeps URLs, revisions and the outbox in memory for
+// tests and local development. It implements URLRepository,
+// OutboxRepository and CodeRangeRepository and is safe for concurrent use.
+type MemoryURLRepository struct {
+	mu        sync.Mutex
+	urls      map[string]*URL // by short code
+	revisions []URLRevision
+	outbox    []*memoryOutboxMessage
+	nextID    int64
+	sequences map[string]int64
+	codeRange int64                // Start of the next code range
+	processed map[string]time.Time // Click event IDs added by AddClicks
+}
+
+type memoryOutboxMessage struct {
+	OutboxMessage
+	claimed bool
+	sentAt  *time.Time
+}
+
+func NewMemoryURLRepository() *MemoryURLRepository {
+	return &MemoryURLRepository{
+		urls:      make(map[string]*URL),
+		sequences: make(map[string]int64),
+		processed: make(map[string]time.Time),
+	}
+}
+
+// enqueue adds event to the outbox. r.mu must be held.
+func (r *MemoryURLRepository) enqueue(event Event) error {
+	seq := r.sequences[event.Topic] + 1
+	if !event.Sequenced {
+		seq = 0
+	}
+
+	payload, err := event.Payload(seq)
+	if err != nil {
+		return err
+	}
+	if event.Sequenced {
+		r.sequences[event.Topic] = seq
+	}
+
+	r.nextID++
+	r.outbox = append(r.outbox, &memoryOutboxMessage{OutboxMessage: OutboxMessage{
+		ID:          r.nextID,
+		Topic:       event.Topic,
+		Key:         event.Key,
+		Payload:     payload,
+		ContentType: event.ContentType,
+	}})
+	return nil
+}
+
+func (r *MemoryURLRepository) Create(ctx context.Context, u *URL, event Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.urls[u.ShortCode]; ok {
+		return ErrConflict
+	}
+	if err := r.enqueue(event); err != nil {
+		return err
+	}
+
+	u.ID = events.NewID()
+	u.Active = true
+	u.UpdatedAt = u.CreatedAt
+	stored := *u
+	r.urls[u.ShortCode] = &stored
+	return nil
+}
+
+func (r *MemoryURLRepository) CreateBatch(ctx context.Context, urls []*URL, event func(u *URL) Event) ([]bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	created := make([]bool, len(urls))
+	for i, u := range urls {
+		if _, ok := r.urls[u.ShortCode]; ok {
+			continue
+		}
+		u.ID = events.NewID()
+		u.Active = true
+		u.UpdatedAt = u.CreatedAt
+		if err := r.enqueue(event(u)); err != nil {
+			return nil, err
+		}
+		stored := *u
+		r.urls[u.ShortCode] = &stored
+		created[i] = true
+	}
+	return created, nil
+}
+
+func (r *MemoryURLRepository) Get(ctx context.Context, shortCode string) (*URL, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	u, ok := r.urls[shortCode]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	found := *u
+	return &found, nil
+}
+
+func (r *MemoryURLRepository) UpdateDestination(ctx context.Context, shortCode, changedBy string,
+	choose func(u *URL) (string, error), event func(u *URL) Event) (*URL, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.urls[shortCode]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	u := *stored
+	newURL, err := choose(&u)
+	if err != nil {
+		return nil, err
+	}
+	if newURL == stored.LongURL {
+		return &u, nil
+	}
+
+	previousURL := stored.LongURL
+	u.LongURL = newURL
+	u.UpdatedAt = time.Now()
+	if err := r.enqueue(event(&u)); err != nil {
+		return nil, err
+	}
+
+	r.revisions = append(r.revisions, URLRevision{
+		ID:              events.NewID(),
+		URLID:           u.ID,
+		ShortCode:       u.ShortCode,
+		PreviousLongURL: previousURL,
+		NewLongURL:      newURL,
+		ChangedBy:       changedBy,
+		ChangedAt:       u.UpdatedAt,
+	})
+	*stored = u
+	return &u, nil
+}
+
+func (r *MemoryURLRepository) SetActive(ctx context.Context, shortCode string, active bool,
+	check func(u *URL) error, event func(u *URL) Event) (*URL, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.urls[shortCode]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	u := *stored
+	if err := check(&u); err != nil {
+		return nil, err
+	}
+	if u.Active == active {
+		return &u, nil
+	}
+
+	u.Active = active
+	u.UpdatedAt = time.Now()
+	if err := r.enqueue(event(&u)); err != nil {
+		return nil, err
+	}
+	*stored = u
+	return &u, nil
+}
+
+func (r *MemoryURLRepository) Delete(ctx context.Context, shortCode string, check func(u *URL) error, event func(u *URL) Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.urls[shortCode]
+	if !ok {
+		return ErrNotFound
+	}
+
+	u := *stored
+	if err := check(&u); err != nil {
+		return err
+	}
+	if err := r.enqueue(event(&u)); err != nil {
+		return err
+	}
+
+	delete(r.urls, shortCode)
+	kept := r.revisions[:0]
+	for _, rev := range r.revisions {
+		if rev.URLID != u.ID {
+			kept = append(kept, rev)
+		}
+	}
+	r.revisions = kept
+	return nil
+}
+
+func (r *MemoryURLRepository) ListByUser(ctx context.Context, q URLListQuery) ([]URL, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	search := strings.ToLower(q.Search)
+	var urls []URL
+	for _, u := range r.urls {
+		expired := u.ExpiresAt != nil && !u.ExpiresAt.After(now)
+		switch {
+		case u.UserID != q.UserID,
+			q.Expired != nil && expired != *q.Expired,
+			q.Active != nil && u.Active != *q.Active,
+			!q.CreatedFrom.IsZero() && u.CreatedAt.Before(q.CreatedFrom),
+			!q.CreatedTo.IsZero() && !u.CreatedAt.Before(q.CreatedTo),
+			search != "" && !strings.Contains(strings.ToLower(u.LongURL), search) &&
+				!strings.Contains(strings.ToLower(u.ShortCode), search):
+			continue
+		}
+		if q.After != nil && !cursorLess(*q.After, CursorOf(u, q.SortBy), q.Ascending) {
+			continue
+		}
+		urls = append(urls, *u)
+	}
+
+	sort.Slice(urls, func(i, j int) bool {
+		return cursorLess(CursorOf(&urls[i], q.SortBy), CursorOf(&urls[j], q.SortBy), q.Ascending)
+	})
+	if len(urls) > q.Limit {
+		urls = urls[:q.Limit]
+	}
+	return urls, nil
+}
+
+// cursorLess reports whether a comes before b in a listing in the given
+// direction
+func cursorLess(a, b URLCursor, ascending bool) bool {
+	var c int
+	switch {
+	case !a.Time.Equal(b.Time):
+		c = a.Time.Compare(b.Time)
+	case a.Count != b.Count:
+		c = cmp.Compare(a.Count, b.Count)
+	default:
+		c = strings.Compare(a.ID, b.ID)
+	}
+	if ascending {
+		return c < 0
+	}
+	return c > 0
+}
+
+func (r *MemoryURLRepository) Revisions(ctx context.Context, urlID string) ([]URLRevision, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var revisions []URLRevision
+	for _, rev := range r.revisions {
+		if rev.URLID == urlID {
+			revisions = append(revisions, rev)
+		}
+	}
+	sort.SliceStable(revisions, func(i, j int) bool {
+		return revisions[i].ChangedAt.After(revisions[j].ChangedAt)
+	})
+	return revisions, nil
+}
+
+func (r *MemoryURLRepository) Revision(ctx context.Context, urlID, revisionID string) (*URLRevision, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, rev := range r.revisions {
+		if rev.ID == revisionID && rev.URLID == urlID {
+			found := rev
+			return &found, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *MemoryURLRepository) AddClicks(ctx context.Context, clicks []Click) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, c := range clicks {
+		if c.EventID != "" {
+			if _, ok := r.processed[c.EventID]; ok {
+				continue
+			}
+			r.processed[c.EventID] = time.Now()
+		}
+
+		u, ok := r.urls[c.ShortCode]
+		if !ok {
+			continue
+		}
+		u.ClickCount++
+		if u.LastAccessed == nil || c.ClickedAt.After(*u.LastAccessed) {
+			at := c.ClickedAt
+			u.LastAccessed = &at
+		}
+	}
+	return nil
+}
+
+func (r *MemoryURLRepository) PruneProcessedClicks(ctx context.Context, before time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, at := range r.processed {
+		if at.Before(before) {
+			delete(r.processed, id)
+		}
+	}
+	return nil
+}
+
+func (r *MemoryURLRepository) NextCodeRange(ctx context.Context) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	start := r.codeRange
+	r.codeRange += CodeRangeSize
+	return start, nil
+}
+
+func (r *MemoryURLRepository) PublishPending(ctx context.Context, limit int, publish func([]OutboxMessage) error) (int, error) {
+	r.mu.Lock()
+	var claimed []*memoryOutboxMessage
+	for _, m := range r.outbox {
+		if len(claimed) == limit {
+			break
+		}
+		if m.sentAt == nil && !m.claimed {
+			m.claimed = true
+			claimed = append(claimed, m)
+		}
+	}
+	r.mu.Unlock()
+
+	if len(claimed) == 0 {
+		return 0, nil
+	}
+
+	messages := make([]OutboxMessage, len(claimed))
+	for i, m := range claimed {
+		messages[i] = m.OutboxMessage
+	}
+	err := publish(messages)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for _, m := range claimed {
+		m.claimed = false
+		if err == nil {
+			m.sentAt = &now
+		}
+	}
+	if err != nil {
+		return 0, err
+	}
+	return len(claimed), nil
+}
+
+func (r *MemoryURLRepository) PruneSent(ctx context.Context, before time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	kept := r.outbox[:0]
+	for _, m := range r.outbox {
+		if m.sentAt == nil || !m.sentAt.Before(before) {
+			kept = append(kept, m)
+		}
+	}
+	r.outbox = kept
+	return nil
+}
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Farhang-Osman/url-shortener-project/common/events"
+)
+
+// MemoryUserRepository keeps users in memory for tests and local
+// development. It is safe for concurrent use.
+type MemoryUserRepository struct {
+	mu    sync.Mutex
+	users map[string]*User // by username
+}
+
+func NewMemoryUserRepository() *MemoryUserRepository {
+	return &MemoryUserRepository{users: make(map[string]*User)}
+}
+
+func (r *MemoryUserRepository) Create(ctx context.Context, u *User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.users {
+		if existing.Username == u.Username || existing.Email == u.Email {
+			return ErrConflict
+		}
+	}
+
+	u.ID = events.NewID()
+	u.CreatedAt = time.Now()
+	stored := *u
+	r.users[u.Username] = &stored
+	return nil
+}
+
+func (r *MemoryUserRepository) GetByUsername(ctx context.Context, username string) (*User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	u, ok := r.users[username]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	found := *u
+	return &found, nil
+}
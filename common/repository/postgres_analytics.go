@@ -0,0 +1,455 @@
+package repository
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"net/netip"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PostgresAnalyticsRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresAnalyticsRepository(pool *pgxpool.Pool) *PostgresAnalyticsRepository {
+	return &PostgresAnalyticsRepository{pool: pool}
+}
+
+// InsertCreated copies the events into the analytics table in one transaction
+func (r *PostgresAnalyticsRepository) InsertCreated(ctx context.Context, events []CreatedEvent) (int, error) {
+	if len(events) == 0 {
+		return 0, nil
+	}
+
+	rows := make([][]any, 0, len(events))
+	for _, e := range events {
+		rows = append(rows, []any{eventIDOf(e.EventID), "url_created", e.ShortCode, e.LongURL,
+			uuidOrNull(e.UserID), e.CreatedAt})
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	inserted, err := copyNewEvents(ctx, tx,
+		[]string{"event_id", "event_type", "short_code", "long_url", "user_id", "timestamp"}, rows)
+	if err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	return len(inserted), nil
+}
+
+// InsertClicks copies the events into the analytics table and adds them to
+// the rollup tables in one transaction
+func (r *PostgresAnalyticsRepository) InsertClicks(ctx context.Context, events []ClickEvent) (int, error) {
+	if len(events) == 0 {
+		return 0, nil
+	}
+
+	ids := make([]pgtype.UUID, len(events))
+	rows := make([][]any, 0, len(events))
+	for i, e := range events {
+		ids[i] = eventIDOf(e.EventID)
+		rows = append(rows, []any{ids[i], "url_clicked", e.ShortCode, e.UserAgent, e.Referer,
+			inetOrNull(e.IPAddress), textOrNull(e.Country), e.ClickedAt})
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	inserted, err := copyNewEvents(ctx, tx,
+		[]string{"event_id", "event_type", "short_code", "user_agent", "referer", "ip_address", "country", "timestamp"}, rows)
+	if err != nil {
+		return 0, err
+	}
+	count := len(inserted)
+
+	rollups := newRollupBatch()
+	for i, e := range events {
+		if inserted[ids[i].Bytes] {
+			// Only the first copy of an event within a batch is counted
+			delete(inserted, ids[i].Bytes)
+			rollups.add(e)
+		}
+	}
+
+	if err := rollups.apply(ctx, tx); err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// copyNewEvents bulk loads rows into the analytics table inside tx. The rows
+// are copied into a temporary staging table first and then inserted with
+// ON CONFLICT DO NOTHING on event_id, since COPY itself can't skip
+// duplicates. columns must start with event_id; the IDs of the rows that
+// were actually inserted are returned.
+func copyNewEvents(ctx context.Context, tx pgx.Tx, columns []string, rows [][]any) (map[[16]byte]bool, error) {
+	_, err := tx.Exec(ctx, "CREATE TEMP TABLE analytics_incoming (LIKE analytics INCLUDING DEFAULTS) ON COMMIT DROP")
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.CopyFrom(ctx, pgx.Identifier{"analytics_incoming"}, columns, pgx.CopyFromRows(rows)); err != nil {
+		return nil, err
+	}
+
+	cols := strings.Join(columns, ", ")
+	result, err := tx.Query(ctx,
+		"INSERT INTO analytics ("+cols+") SELECT "+cols+" FROM analytics_incoming ON CONFLICT (event_id) DO NOTHING RETURNING event_id")
+	if err != nil {
+		return nil, err
+	}
+	defer result.Close()
+
+	inserted := make(map[[16]byte]bool, len(rows))
+	for result.Next() {
+		var id pgtype.UUID
+		if err := result.Scan(&id); err != nil {
+			return nil, err
+		}
+		inserted[id.Bytes] = true
+	}
+	return inserted, result.Err()
+}
+
+// eventIDOf parses a producer-assigned event ID. Legacy events without one
+// get a random ID, which stores them but can't deduplicate them.
+func eventIDOf(s string) pgtype.UUID {
+	var id pgtype.UUID
+	if s != "" && id.Scan(s) == nil {
+		return id
+	}
+
+	rand.Read(id.Bytes[:])
+	id.Bytes[6] = (id.Bytes[6] & 0x0f) | 0x40 // version 4
+	id.Bytes[8] = (id.Bytes[8] & 0x3f) | 0x80 // RFC 4122 variant
+	id.Valid = true
+	return id
+}
+
+// uuidOrNull converts an optional UUID string for the binary COPY protocol
+func uuidOrNull(s string) any {
+	var id pgtype.UUID
+	if s == "" || id.Scan(s) != nil {
+		return nil
+	}
+	return id
+}
+
+// inetOrNull converts an optional IP address string for the binary COPY protocol
+func inetOrNull(s string) any {
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return nil
+	}
+	return addr
+}
+
+func textOrNull(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+func (r *PostgresAnalyticsRepository) LinkOwner(ctx context.Context, shortCode string) (string, error) {
+	var ownerID *string
+	err := r.pool.QueryRow(ctx,
+		"SELECT user_id::text FROM analytics WHERE event_type = 'url_created' AND short_code = $1 ORDER BY timestamp DESC LIMIT 1",
+		shortCode).Scan(&ownerID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", ErrNotFound
+		}
+		return "", err
+	}
+	if ownerID == nil {
+		return "", nil
+	}
+	return *ownerID, nil
+}
+
+// truncUnit maps a granularity to a date_trunc unit
+func truncUnit(granularity string) string {
+	switch granularity {
+	case GranularityHour, GranularityMonth:
+		return granularity
+	default:
+		return GranularityDay
+	}
+}
+
+func (r *PostgresAnalyticsRepository) queryTimeSeries(ctx context.Context, query string, args ...any) ([]TimeBucket, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var series []TimeBucket
+	for rows.Next() {
+		var b TimeBucket
+		if err := rows.Scan(&b.Start, &b.Clicks, &b.UniqueClicks); err != nil {
+			return nil, err
+		}
+		b.Start = b.Start.UTC()
+		series = append(series, b)
+	}
+	return series, rows.Err()
+}
+
+func (r *PostgresAnalyticsRepository) queryCounts(ctx context.Context, query string, args ...any) ([]CountEntry, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []CountEntry
+	for rows.Next() {
+		var e CountEntry
+		if err := rows.Scan(&e.Value, &e.Count); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// Stats queries take the short codes to report on as a filter on
+// short_code using $1, and the range as $2 and $3. Click counts are summed
+// from the click rollup table and distinct client IPs counted from the
+// visitor table of the same bucket size.
+
+// totalsQuery counts the clicks and distinct client IPs in the range
+func totalsQuery(tables rollupTables, filter string) string {
+	return fmt.Sprintf(`
+		SELECT (SELECT COALESCE(SUM(clicks), 0)::bigint
+		        FROM %[1]s
+		        WHERE dimension = 'total' AND %[3]s AND bucket_start >= $2 AND bucket_start < $3),
+		       (SELECT COUNT(DISTINCT ip_address)
+		        FROM %[2]s
+		        WHERE %[3]s AND bucket_start >= $2 AND bucket_start < $3)`,
+		tables.clicks, tables.visitors, filter)
+}
+
+// seriesQuery buckets the clicks in the range by the date_trunc unit $4
+func seriesQuery(tables rollupTables, filter string) string {
+	return fmt.Sprintf(`
+		WITH clicks AS (
+			SELECT date_trunc($4, bucket_start AT TIME ZONE 'UTC') AT TIME ZONE 'UTC' AS bucket, SUM(clicks)::bigint AS clicks
+			FROM %[1]s
+			WHERE dimension = 'total' AND %[3]s AND bucket_start >= $2 AND bucket_start < $3
+			GROUP BY bucket
+		), ips AS (
+			SELECT date_trunc($4, bucket_start AT TIME ZONE 'UTC') AT TIME ZONE 'UTC' AS bucket, COUNT(DISTINCT ip_address) AS ips
+			FROM %[2]s
+			WHERE %[3]s AND bucket_start >= $2 AND bucket_start < $3
+			GROUP BY bucket
+		)
+		SELECT clicks.bucket, clicks.clicks, COALESCE(ips.ips, 0)
+		FROM clicks LEFT JOIN ips USING (bucket)
+		ORDER BY clicks.bucket`, tables.clicks, tables.visitors, filter)
+}
+
+// topValuesQuery builds a top-N query over one rollup dimension ($4) of one
+// short code, returning $5 entries
+func topValuesQuery(tables rollupTables) string {
+	return fmt.Sprintf(`
+		SELECT value, SUM(clicks)::bigint AS clicks
+		FROM %s
+		WHERE short_code = $1 AND bucket_start >= $2 AND bucket_start < $3 AND dimension = $4
+		GROUP BY value
+		ORDER BY clicks DESC, value
+		LIMIT $5`, tables.clicks)
+}
+
+func (r *PostgresAnalyticsRepository) LinkStats(ctx context.Context, shortCode string, q StatsQuery) (*LinkStats, error) {
+	const link = "short_code = $1"
+	tables, from, to := rollupRange(q)
+	stats := &LinkStats{}
+
+	err := r.pool.QueryRow(ctx, totalsQuery(tables, link), shortCode, from, to).Scan(&stats.TotalClicks, &stats.UniqueClicks)
+	if err != nil {
+		return nil, err
+	}
+
+	stats.Series, err = r.queryTimeSeries(ctx, seriesQuery(tables, link), shortCode, from, to, truncUnit(q.Granularity))
+	if err != nil {
+		return nil, err
+	}
+
+	top := []struct {
+		dimension string
+		entries   *[]CountEntry
+	}{
+		{dimensionReferer, &stats.TopReferers},
+		{dimensionUserAgent, &stats.TopUserAgents},
+		{dimensionCountry, &stats.TopCountries},
+		{dimensionRefererDomain, &stats.TopRefererDomains},
+		{dimensionDeviceClass, &stats.TopDeviceClasses},
+	}
+	for _, t := range top {
+		*t.entries, err = r.queryCounts(ctx, topValuesQuery(tables), shortCode, from, to, t.dimension, q.TopLimit)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return stats, nil
+}
+
+func (r *PostgresAnalyticsRepository) UserSummary(ctx context.Context, userID string, q StatsQuery) (*UserSummary, error) {
+	// The user's links, as recorded by their url_created events
+	const userLinks = `SELECT DISTINCT short_code FROM analytics WHERE event_type = 'url_created' AND user_id = $1`
+	const owned = "short_code IN (" + userLinks + ")"
+	tables, from, to := rollupRange(q)
+
+	summary := &UserSummary{}
+
+	err := r.pool.QueryRow(ctx, "SELECT COUNT(*) FROM ("+userLinks+") AS links", userID).Scan(&summary.TotalLinks)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.pool.QueryRow(ctx, totalsQuery(tables, owned), userID, from, to).Scan(&summary.TotalClicks, &summary.UniqueClicks)
+	if err != nil {
+		return nil, err
+	}
+
+	summary.Series, err = r.queryTimeSeries(ctx, seriesQuery(tables, owned), userID, from, to, truncUnit(q.Granularity))
+	if err != nil {
+		return nil, err
+	}
+
+	// Distinct client IPs are only counted for the top links
+	rows, err := r.pool.Query(ctx, `
+		WITH top AS (
+			SELECT short_code, SUM(clicks)::bigint AS clicks
+			FROM `+tables.clicks+`
+			WHERE dimension = 'total' AND `+owned+` AND bucket_start >= $2 AND bucket_start < $3
+			GROUP BY short_code
+			ORDER BY clicks DESC, short_code
+			LIMIT $4
+		)
+		SELECT top.short_code,
+		       COALESCE((SELECT u.long_url FROM analytics u
+		                 WHERE u.event_type = 'url_created' AND u.short_code = top.short_code
+		                 ORDER BY u.timestamp DESC LIMIT 1), ''),
+		       top.clicks,
+		       (SELECT COUNT(DISTINCT v.ip_address) FROM `+tables.visitors+` v
+		        WHERE v.short_code = top.short_code AND v.bucket_start >= $2 AND v.bucket_start < $3)
+		FROM top
+		ORDER BY top.clicks DESC, top.short_code`,
+		userID, from, to, q.TopLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var l LinkClicks
+		if err := rows.Scan(&l.ShortCode, &l.LongURL, &l.Clicks, &l.UniqueClicks); err != nil {
+			return nil, err
+		}
+		summary.TopLinks = append(summary.TopLinks, l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return summary, nil
+}
+
+func (r *PostgresAnalyticsRepository) DeleteLink(ctx context.Context, shortCode string, deletedAt time.Time) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, "DELETE FROM analytics WHERE short_code = $1 AND timestamp <= $2", shortCode, deletedAt); err != nil {
+		return err
+	}
+	for _, table := range rollupTableNames {
+		if _, err := tx.Exec(ctx, "DELETE FROM "+table+" WHERE short_code = $1 AND bucket_start <= $2", shortCode, deletedAt); err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+// RebuildRollups locks the rollup tables for the duration, which holds back
+// consumers until the rebuild commits
+func (r *PostgresAnalyticsRepository) RebuildRollups(ctx context.Context, from, to time.Time) (time.Time, time.Time, int64, error) {
+	from, to = wholeDays(from, to)
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return from, to, 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, "LOCK TABLE "+strings.Join(rollupTableNames, ", ")+" IN EXCLUSIVE MODE"); err != nil {
+		return from, to, 0, err
+	}
+	for _, table := range rollupTableNames {
+		if _, err := tx.Exec(ctx, "DELETE FROM "+table+" WHERE bucket_start >= $1 AND bucket_start < $2", from, to); err != nil {
+			return from, to, 0, err
+		}
+	}
+
+	rows, err := tx.Query(ctx, `
+		SELECT short_code, timestamp, COALESCE(user_agent, ''), COALESCE(referer, ''), COALESCE(country, ''),
+		       COALESCE(host(ip_address), '')
+		FROM analytics
+		WHERE event_type = 'url_clicked' AND short_code IS NOT NULL AND timestamp >= $1 AND timestamp < $2`,
+		from, to)
+	if err != nil {
+		return from, to, 0, err
+	}
+
+	// Aggregate everything first; the connection is busy until rows are drained
+	batch := newRollupBatch()
+	var total int64
+	for rows.Next() {
+		var click ClickEvent
+		if err := rows.Scan(&click.ShortCode, &click.ClickedAt, &click.UserAgent, &click.Referer, &click.Country, &click.IPAddress); err != nil {
+			rows.Close()
+			return from, to, 0, err
+		}
+		batch.add(click)
+		total++
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return from, to, 0, err
+	}
+
+	if err := batch.apply(ctx, tx); err != nil {
+		return from, to, 0, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return from, to, 0, err
+	}
+	return from, to, total, nil
+}
@@ -0,0 +1,609 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresURLRepository stores URLs, their revisions and the outbox in
+// Postgres. It implements URLRepository, OutboxRepository and
+// CodeRangeRepository.
+type PostgresURLRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresURLRepository(pool *pgxpool.Pool) *PostgresURLRepository {
+	return &PostgresURLRepository{pool: pool}
+}
+
+const urlColumns = "id, short_code, long_url, user_id::text, is_active, expires_at, created_at, updated_at, click_count, last_accessed, tags"
+
+func scanURL(row pgx.Row) (*URL, error) {
+	var u URL
+	var userID *string
+	var createdAt, updatedAt *time.Time
+	var clickCount *int64
+	err := row.Scan(&u.ID, &u.ShortCode, &u.LongURL, &userID, &u.Active, &u.ExpiresAt, &createdAt, &updatedAt, &clickCount, &u.LastAccessed, &u.Tags)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	if userID != nil {
+		u.UserID = *userID
+	}
+	if createdAt != nil {
+		u.CreatedAt = *createdAt
+	}
+	if updatedAt != nil {
+		u.UpdatedAt = *updatedAt
+	}
+	if clickCount != nil {
+		u.ClickCount = *clickCount
+	}
+	return &u, nil
+}
+
+func nullIfEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// tagsOrEmpty keeps nil tags from being written as NULL
+func tagsOrEmpty(tags []string) []string {
+	if tags == nil {
+		return []string{}
+	}
+	return tags
+}
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505" // 23505 is unique_violation
+}
+
+// enqueue writes event to the outbox inside tx, allocating its sequence
+// number first if it needs one. The counter row stays locked until tx ends,
+// so numbers are gapless and handed out in commit order.
+func enqueue(ctx context.Context, tx pgx.Tx, event Event) error {
+	var seq int64
+	if event.Sequenced {
+		err := tx.QueryRow(ctx, `
+			INSERT INTO event_sequences (topic, value) VALUES ($1, 1)
+			ON CONFLICT (topic) DO UPDATE SET value = event_sequences.value + 1
+			RETURNING value`,
+			event.Topic).Scan(&seq)
+		if err != nil {
+			return fmt.Errorf("failed to allocate sequence number: %w", err)
+		}
+	}
+
+	payload, err := event.Payload(seq)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx,
+		"INSERT INTO outbox (topic, message_key, payload, content_type) VALUES ($1, $2, $3, $4)",
+		event.Topic, nullIfEmpty(event.Key), payload, nullIfEmpty(event.ContentType))
+	return err
+}
+
+// Create claims the short code with the insert itself: ON CONFLICT DO
+// NOTHING returns no row if it is taken, even by a transaction committed
+// after this one started.
+func (r *PostgresURLRepository) Create(ctx context.Context, u *URL, event Event) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO urls (short_code, long_url, user_id, expires_at, created_at, tags) VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (short_code) DO NOTHING
+		RETURNING id`,
+		u.ShortCode, u.LongURL, nullIfEmpty(u.UserID), u.ExpiresAt, u.CreatedAt, tagsOrEmpty(u.Tags)).Scan(&u.ID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) || isUniqueViolation(err) {
+			return ErrConflict
+		}
+		return err
+	}
+	u.Active = true
+
+	if err := enqueue(ctx, tx, event); err != nil {
+		return fmt.Errorf("failed to queue event: %w", err)
+	}
+	return tx.Commit(ctx)
+}
+
+// CreateBatch sends all inserts in one round trip. ON CONFLICT DO NOTHING
+// turns a taken short code into an empty result instead of aborting the
+// transaction.
+func (r *PostgresURLRepository) CreateBatch(ctx context.Context, urls []*URL, event func(u *URL) Event) ([]bool, error) {
+	created := make([]bool, len(urls))
+	if len(urls) == 0 {
+		return created, nil
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	batch := &pgx.Batch{}
+	for _, u := range urls {
+		batch.Queue(`
+			INSERT INTO urls (short_code, long_url, user_id, expires_at, created_at, tags) VALUES ($1, $2, $3, $4, $5, $6)
+			ON CONFLICT (short_code) DO NOTHING
+			RETURNING id`,
+			u.ShortCode, u.LongURL, nullIfEmpty(u.UserID), u.ExpiresAt, u.CreatedAt, tagsOrEmpty(u.Tags))
+	}
+	results := tx.SendBatch(ctx, batch)
+	for i, u := range urls {
+		err := results.QueryRow().Scan(&u.ID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			results.Close()
+			return nil, err
+		}
+		u.Active = true
+		created[i] = true
+	}
+	if err := results.Close(); err != nil {
+		return nil, err
+	}
+
+	for i, u := range urls {
+		if !created[i] {
+			continue
+		}
+		if err := enqueue(ctx, tx, event(u)); err != nil {
+			return nil, fmt.Errorf("failed to queue event: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+func (r *PostgresURLRepository) Get(ctx context.Context, shortCode string) (*URL, error) {
+	return scanURL(r.pool.QueryRow(ctx, "SELECT "+urlColumns+" FROM urls WHERE short_code = $1", shortCode))
+}
+
+func (r *PostgresURLRepository) UpdateDestination(ctx context.Context, shortCode, changedBy string,
+	choose func(u *URL) (string, error), event func(u *URL) Event) (*URL, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	u, err := scanURL(tx.QueryRow(ctx, "SELECT "+urlColumns+" FROM urls WHERE short_code = $1 FOR UPDATE", shortCode))
+	if err != nil {
+		return nil, err
+	}
+
+	newURL, err := choose(u)
+	if err != nil {
+		return nil, err
+	}
+	if newURL == u.LongURL {
+		return u, nil
+	}
+
+	previousURL := u.LongURL
+	err = tx.QueryRow(ctx,
+		"UPDATE urls SET long_url = $1, updated_at = NOW() WHERE id = $2 RETURNING updated_at",
+		newURL, u.ID).Scan(&u.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update URL: %w", err)
+	}
+	u.LongURL = newURL
+
+	_, err = tx.Exec(ctx,
+		"INSERT INTO url_revisions (url_id, short_code, previous_long_url, new_long_url, changed_by) VALUES ($1, $2, $3, $4, $5)",
+		u.ID, u.ShortCode, previousURL, newURL, nullIfEmpty(changedBy))
+	if err != nil {
+		return nil, fmt.Errorf("failed to record URL revision: %w", err)
+	}
+
+	if err := enqueue(ctx, tx, event(u)); err != nil {
+		return nil, fmt.Errorf("failed to queue event: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+func (r *PostgresURLRepository) SetActive(ctx context.Context, shortCode string, active bool,
+	check func(u *URL) error, event func(u *URL) Event) (*URL, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	u, err := scanURL(tx.QueryRow(ctx, "SELECT "+urlColumns+" FROM urls WHERE short_code = $1 FOR UPDATE", shortCode))
+	if err != nil {
+		return nil, err
+	}
+	if err := check(u); err != nil {
+		return nil, err
+	}
+	if u.Active == active {
+		return u, nil
+	}
+
+	err = tx.QueryRow(ctx,
+		"UPDATE urls SET is_active = $1, updated_at = NOW() WHERE id = $2 RETURNING updated_at",
+		active, u.ID).Scan(&u.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update URL: %w", err)
+	}
+	u.Active = active
+
+	if err := enqueue(ctx, tx, event(u)); err != nil {
+		return nil, fmt.Errorf("failed to queue event: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// Delete relies on url_revisions cascading from urls
+func (r *PostgresURLRepository) Delete(ctx context.Context, shortCode string, check func(u *URL) error, event func(u *URL) Event) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	u, err := scanURL(tx.QueryRow(ctx, "SELECT "+urlColumns+" FROM urls WHERE short_code = $1 FOR UPDATE", shortCode))
+	if err != nil {
+		return err
+	}
+	if err := check(u); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, "DELETE FROM urls WHERE id = $1", u.ID); err != nil {
+		return fmt.Errorf("failed to delete URL: %w", err)
+	}
+
+	if err := enqueue(ctx, tx, event(u)); err != nil {
+		return fmt.Errorf("failed to queue event: %w", err)
+	}
+	return tx.Commit(ctx)
+}
+
+// urlSortExprs are the SQL expressions behind the URLSort keys, with NULLs
+// replaced the same way CursorOf does
+var urlSortExprs = map[string]string{
+	URLSortCreatedAt:    "COALESCE(created_at, 'epoch')",
+	URLSortClickCount:   "COALESCE(click_count, 0)",
+	URLSortLastAccessed: "COALESCE(last_accessed, 'epoch')",
+}
+
+// escapeLike escapes the ILIKE wildcards in s
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// ListByUser seeks past q.After on (sort key, id) rather than using OFFSET.
+// With the default sort that is an index range scan on
+// idx_urls_user_id_created_at; other sorts read the user's URLs through
+// idx_urls_user_id.
+func (r *PostgresURLRepository) ListByUser(ctx context.Context, q URLListQuery) ([]URL, error) {
+	sortExpr, ok := urlSortExprs[q.SortBy]
+	if !ok {
+		sortExpr = urlSortExprs[URLSortCreatedAt]
+	}
+
+	var args []any
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	where := []string{"user_id = " + arg(uuidOrNull(q.UserID))}
+	if q.Expired != nil {
+		if *q.Expired {
+			where = append(where, "expires_at IS NOT NULL AND expires_at <= NOW()")
+		} else {
+			where = append(where, "(expires_at IS NULL OR expires_at > NOW())")
+		}
+	}
+	if q.Active != nil {
+		where = append(where, "is_active = "+arg(*q.Active))
+	}
+	if !q.CreatedFrom.IsZero() {
+		where = append(where, "created_at >= "+arg(q.CreatedFrom))
+	}
+	if !q.CreatedTo.IsZero() {
+		where = append(where, "created_at < "+arg(q.CreatedTo))
+	}
+	if q.Search != "" {
+		pattern := arg("%" + escapeLike(q.Search) + "%")
+		where = append(where, "(long_url ILIKE "+pattern+" OR short_code ILIKE "+pattern+")")
+	}
+
+	order, cmp := "DESC", "<"
+	if q.Ascending {
+		order, cmp = "ASC", ">"
+	}
+	if q.After != nil {
+		var key any = q.After.Time
+		if q.SortBy == URLSortClickCount {
+			key = q.After.Count
+		}
+		where = append(where, "("+sortExpr+", id) "+cmp+" ("+arg(key)+", "+arg(uuidOrNull(q.After.ID))+")")
+	}
+
+	rows, err := r.pool.Query(ctx,
+		"SELECT "+urlColumns+" FROM urls WHERE "+strings.Join(where, " AND ")+
+			" ORDER BY "+sortExpr+" "+order+", id "+order+" LIMIT "+arg(q.Limit),
+		args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var urls []URL
+	for rows.Next() {
+		u, err := scanURL(rows)
+		if err != nil {
+			return nil, err
+		}
+		urls = append(urls, *u)
+	}
+	return urls, rows.Err()
+}
+
+const revisionColumns = "id, url_id, short_code, previous_long_url, new_long_url, changed_by::text, changed_at"
+
+func scanRevision(row pgx.Row) (*URLRevision, error) {
+	var rev URLRevision
+	var changedBy *string
+	if err := row.Scan(&rev.ID, &rev.URLID, &rev.ShortCode, &rev.PreviousLongURL, &rev.NewLongURL, &changedBy, &rev.ChangedAt); err != nil {
+		return nil, err
+	}
+	if changedBy != nil {
+		rev.ChangedBy = *changedBy
+	}
+	return &rev, nil
+}
+
+func (r *PostgresURLRepository) Revisions(ctx context.Context, urlID string) ([]URLRevision, error) {
+	rows, err := r.pool.Query(ctx,
+		"SELECT "+revisionColumns+" FROM url_revisions WHERE url_id = $1 ORDER BY changed_at DESC",
+		urlID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var revisions []URLRevision
+	for rows.Next() {
+		rev, err := scanRevision(rows)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, *rev)
+	}
+	return revisions, rows.Err()
+}
+
+func (r *PostgresURLRepository) Revision(ctx context.Context, urlID, revisionID string) (*URLRevision, error) {
+	rev, err := scanRevision(r.pool.QueryRow(ctx,
+		"SELECT "+revisionColumns+" FROM url_revisions WHERE id = $1 AND url_id = $2",
+		revisionID, urlID))
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.Is(err, pgx.ErrNoRows) || (errors.As(err, &pgErr) && pgErr.Code == "22P02") { // 22P02 is invalid_text_representation
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return rev, nil
+}
+
+func (r *PostgresURLRepository) AddClicks(ctx context.Context, clicks []Click) error {
+	if len(clicks) == 0 {
+		return nil
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// Claim the event IDs; those claimed before were counted back then
+	ids := make([]pgtype.UUID, len(clicks))
+	for i, c := range clicks {
+		if c.EventID != "" {
+			ids[i].Scan(c.EventID) // Invalid IDs stay NULL and are always counted
+		}
+	}
+	rows, err := tx.Query(ctx, `
+		INSERT INTO processed_events (event_id)
+		SELECT id FROM unnest($1::uuid[]) AS id WHERE id IS NOT NULL
+		ON CONFLICT (event_id) DO NOTHING
+		RETURNING event_id`, ids)
+	if err != nil {
+		return err
+	}
+	claimed := make(map[[16]byte]bool)
+	for rows.Next() {
+		var id pgtype.UUID
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return err
+		}
+		claimed[id.Bytes] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	tallies := newClickTallies()
+	for i, c := range clicks {
+		if ids[i].Valid {
+			if !claimed[ids[i].Bytes] {
+				continue
+			}
+			// Only the first copy of an event within a batch is counted
+			delete(claimed, ids[i].Bytes)
+		}
+		tallies.add(c)
+	}
+	if len(tallies.counts) > 0 {
+		shortCodes, counts, accessed := tallies.columns()
+		_, err = tx.Exec(ctx, `
+			UPDATE urls AS u
+			SET click_count = COALESCE(u.click_count, 0) + c.clicks,
+			    last_accessed = GREATEST(u.last_accessed, c.last_accessed)
+			FROM unnest($1::text[], $2::bigint[], $3::timestamptz[]) AS c(short_code, clicks, last_accessed)
+			WHERE u.short_code = c.short_code`,
+			shortCodes, counts, accessed)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *PostgresURLRepository) PruneProcessedClicks(ctx context.Context, before time.Time) error {
+	_, err := r.pool.Exec(ctx, "DELETE FROM processed_events WHERE processed_at < $1", before)
+	return err
+}
+
+func (r *PostgresURLRepository) NextCodeRange(ctx context.Context) (int64, error) {
+	var start int64
+	err := r.pool.QueryRow(ctx, "SELECT nextval('short_code_ids')").Scan(&start)
+	return start, err
+}
+
+func (r *PostgresURLRepository) PublishPending(ctx context.Context, limit int, publish func([]OutboxMessage) error) (int, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx,
+		"SELECT id, topic, message_key, payload, content_type FROM outbox WHERE sent_at IS NULL ORDER BY id LIMIT $1 FOR UPDATE SKIP LOCKED",
+		limit)
+	if err != nil {
+		return 0, err
+	}
+
+	var ids []int64
+	var messages []OutboxMessage
+	for rows.Next() {
+		var msg OutboxMessage
+		var key, contentType *string
+		if err := rows.Scan(&msg.ID, &msg.Topic, &key, &msg.Payload, &contentType); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		if key != nil {
+			msg.Key = *key
+		}
+		if contentType != nil {
+			msg.ContentType = *contentType
+		}
+		ids = append(ids, msg.ID)
+		messages = append(messages, msg)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	if len(messages) == 0 {
+		return 0, nil
+	}
+
+	if err := publish(messages); err != nil {
+		// Record the failure so stuck rows are visible
+		_, updateErr := tx.Exec(ctx,
+			"UPDATE outbox SET attempts = attempts + 1, last_error = $2 WHERE id = ANY($1)",
+			ids, err.Error())
+		if updateErr == nil {
+			tx.Commit(ctx)
+		}
+		return 0, err
+	}
+
+	if _, err := tx.Exec(ctx, "UPDATE outbox SET sent_at = NOW() WHERE id = ANY($1)", ids); err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	return len(ids), nil
+}
+
+func (r *PostgresURLRepository) PruneSent(ctx context.Context, before time.Time) error {
+	_, err := r.pool.Exec(ctx, "DELETE FROM outbox WHERE sent_at IS NOT NULL AND sent_at < $1", before)
+	return err
+}
+
+// clickTallies sums clicks per short code for one counter update
+type clickTallies struct {
+	counts       map[string]int64
+	lastAccessed map[string]time.Time
+}
+
+func newClickTallies() *clickTallies {
+	return &clickTallies{counts: make(map[string]int64), lastAccessed: make(map[string]time.Time)}
+}
+
+func (t *clickTallies) add(c Click) {
+	t.counts[c.ShortCode]++
+	if c.ClickedAt.After(t.lastAccessed[c.ShortCode]) {
+		t.lastAccessed[c.ShortCode] = c.ClickedAt
+	}
+}
+
+// columns returns the tallies as the arrays the counter update unnests,
+// ordered by short code so concurrent updates lock rows in the same order
+func (t *clickTallies) columns() ([]string, []int64, []time.Time) {
+	shortCodes := make([]string, 0, len(t.counts))
+	for code := range t.counts {
+		shortCodes = append(shortCodes, code)
+	}
+	slices.Sort(shortCodes)
+
+	counts := make([]int64, len(shortCodes))
+	accessed := make([]time.Time, len(shortCodes))
+	for i, code := range shortCodes {
+		counts[i], accessed[i] = t.counts[code], t.lastAccessed[code]
+	}
+	return shortCodes, counts, accessed
+}
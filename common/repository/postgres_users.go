@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PostgresUserRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresUserRepository(pool *pgxpool.Pool) *PostgresUserRepository {
+	return &PostgresUserRepository{pool: pool}
+}
+
+func (r *PostgresUserRepository) Create(ctx context.Context, u *User) error {
+	var createdAt *time.Time
+	err := r.pool.QueryRow(ctx,
+		"INSERT INTO users (username, email, password_hash) VALUES ($1, $2, $3) RETURNING id, created_at",
+		u.Username, u.Email, u.PasswordHash).Scan(&u.ID, &createdAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return ErrConflict
+		}
+		return err
+	}
+	if createdAt != nil {
+		u.CreatedAt = *createdAt
+	}
+	return nil
+}
+
+func (r *PostgresUserRepository) GetByUsername(ctx context.Context, username string) (*User, error) {
+	var u User
+	var createdAt *time.Time
+	err := r.pool.QueryRow(ctx,
+		"SELECT id, username, email, password_hash, created_at FROM users WHERE username = $1",
+		username).Scan(&u.ID, &u.Username, &u.Email, &u.PasswordHash, &createdAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	if createdAt != nil {
+		u.CreatedAt = *createdAt
+	}
+	return &u, nil
+}
@@ -0,0 +1,306 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrNotFound = errors.New("not found")
+	ErrConflict = errors.New("already exists")
+)
+
+// URL is one row of the urls table. UserID is empty for anonymous links.
+// Inactive links are kept but no longer redirect.
+type URL struct {
+	ID           string
+	ShortCode    string
+	LongURL      string
+	UserID       string
+	Active       bool
+	ExpiresAt    *time.Time
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	ClickCount   int64
+	LastAccessed *time.Time
+	Tags         []string
+}
+
+// URLRevision records one change of a URL's destination
+type URLRevision struct {
+	ID              string
+	URLID           string
+	ShortCode       string
+	PreviousLongURL string
+	NewLongURL      string
+	ChangedBy       string
+	ChangedAt       time.Time
+}
+
+// Click is one url_clicked event to add to a URL's counters. EventID is the
+// producer-assigned event ID, empty for legacy events without one.
+type Click struct {
+	EventID   string
+	ShortCode string
+	ClickedAt time.Time
+}
+
+// Event is queued in the outbox in the same transaction as the change it
+// describes. If Sequenced is set, Payload receives the next gapless
+// sequence number for Topic; otherwise it receives 0.
+type Event struct {
+	Topic       string
+	Key         string
+	ContentType string
+	Sequenced   bool
+	Payload     func(seq int64) ([]byte, error)
+}
+
+// OutboxMessage is a queued event waiting to be published. ContentType is
+// empty for messages queued before it was recorded.
+type OutboxMessage struct {
+	ID          int64
+	Topic       string
+	Key         string
+	Payload     []byte
+	ContentType string
+}
+
+// URL list sort keys
+const (
+	URLSortCreatedAt    = "created_at"
+	URLSortClickCount   = "click_count"
+	URLSortLastAccessed = "last_accessed"
+)
+
+// URLCursor is the position of a URL in a sorted listing: its sort key and,
+// to break ties, its ID. Time holds created_at or last_accessed and Count
+// click_count, depending on the sort key.
+type URLCursor struct {
+	Time  time.Time
+	Count int64
+	ID    string
+}
+
+// CursorOf returns the position of u in a listing sorted by sortBy. URLs
+// that were never accessed sort as if last accessed at the Unix epoch.
+func CursorOf(u *URL, sortBy string) URLCursor {
+	c := URLCursor{ID: u.ID}
+	switch sortBy {
+	case URLSortClickCount:
+		c.Count = u.ClickCount
+	case URLSortLastAccessed:
+		c.Time = time.Unix(0, 0).UTC()
+		if u.LastAccessed != nil {
+			c.Time = *u.LastAccessed
+		}
+	default:
+		c.Time = u.CreatedAt
+	}
+	return c
+}
+
+// URLListQuery selects a page of one user's URLs. Nil and zero filters match
+// everything.
+type URLListQuery struct {
+	UserID      string
+	SortBy      string // One of the URLSort keys, URLSortCreatedAt if empty
+	Ascending   bool
+	Expired     *bool
+	Active      *bool
+	CreatedFrom time.Time  // Inclusive
+	CreatedTo   time.Time  // Exclusive
+	Search      string     // Case-insensitive substring of LongURL or ShortCode
+	After       *URLCursor // Continue after this position
+	Limit       int
+}
+
+type URLRepository interface {
+	// Create stores u as an active URL and queues event with it. It fills in
+	// u.ID and returns ErrConflict if the short code is taken.
+	Create(ctx context.Context, u *URL, event Event) error
+	// CreateBatch stores urls as active URLs in one transaction and queues
+	// event(u) for each one stored. URLs whose short code is taken, also by
+	// an earlier URL of the batch, are skipped. It fills in ID of the stored
+	// URLs and reports for each URL whether it was stored.
+	CreateBatch(ctx context.Context, urls []*URL, event func(u *URL) Event) ([]bool, error)
+	// Get returns the URL with shortCode or ErrNotFound
+	Get(ctx context.Context, shortCode string) (*URL, error)
+	// UpdateDestination locks the URL with shortCode and passes it to choose,
+	// which returns the new destination or an error to abort with. If the
+	// destination changes, the URL is updated, a revision by changedBy is
+	// recorded and event(u) is queued, all in one transaction.
+	UpdateDestination(ctx context.Context, shortCode, changedBy string,
+		choose func(u *URL) (string, error), event func(u *URL) Event) (*URL, error)
+	// SetActive locks the URL with shortCode and passes it to check, which
+	// returns an error to abort with. If the active state changes, the URL is
+	// updated and event(u) is queued in the same transaction.
+	SetActive(ctx context.Context, shortCode string, active bool,
+		check func(u *URL) error, event func(u *URL) Event) (*URL, error)
+	// Delete locks the URL with shortCode and passes it to check, which
+	// returns an error to abort with. The URL and its revisions are then
+	// deleted and event(u) is queued in the same transaction.
+	Delete(ctx context.Context, shortCode string, check func(u *URL) error, event func(u *URL) Event) error
+	// ListByUser returns the URLs selected by q in order
+	ListByUser(ctx context.Context, q URLListQuery) ([]URL, error)
+	// Revisions lists a URL's revisions, newest first
+	Revisions(ctx context.Context, urlID string) ([]URLRevision, error)
+	// Revision returns one of a URL's revisions or ErrNotFound
+	Revision(ctx context.Context, urlID, revisionID string) (*URLRevision, error)
+	// AddClicks adds clicks to the counters of their short codes. Event IDs
+	// are recorded with the increment and clicks whose event ID was already
+	// added are skipped, so redelivered events are counted once.
+	AddClicks(ctx context.Context, clicks []Click) error
+	// PruneProcessedClicks forgets the event IDs AddClicks recorded before
+	// the given time; those events would be counted again if redelivered
+	PruneProcessedClicks(ctx context.Context, before time.Time) error
+}
+
+// CodeRangeSize is the number of IDs in a range handed out by
+// CodeRangeRepository
+const CodeRangeSize = 1000
+
+type CodeRangeRepository interface {
+	// NextCodeRange reserves the IDs [start, start+CodeRangeSize) for the
+	// caller. Ranges never overlap and are never handed out twice.
+	NextCodeRange(ctx context.Context) (start int64, err error)
+}
+
+type OutboxRepository interface {
+	// PublishPending claims up to limit unsent messages in queue order and
+	// passes them to publish. They are marked sent if publish succeeds and
+	// the failure is recorded on them otherwise. Concurrent callers never
+	// claim the same message. It returns the number of messages sent.
+	PublishPending(ctx context.Context, limit int, publish func([]OutboxMessage) error) (int, error)
+	// PruneSent deletes messages sent before the given time
+	PruneSent(ctx context.Context, before time.Time) error
+}
+
+type User struct {
+	ID           string
+	Username     string
+	Email        string
+	PasswordHash []byte
+	CreatedAt    time.Time
+}
+
+type UserRepository interface {
+	// Create stores u and fills in its ID. It returns ErrConflict if the
+	// username or email is taken.
+	Create(ctx context.Context, u *User) error
+	// GetByUsername returns the user with username or ErrNotFound
+	GetByUsername(ctx context.Context, username string) (*User, error)
+}
+
+// CreatedEvent is a url_created analytics row
+type CreatedEvent struct {
+	EventID   string
+	ShortCode string
+	LongURL   string
+	UserID    string
+	CreatedAt time.Time
+}
+
+// ClickEvent is a url_clicked analytics row
+type ClickEvent struct {
+	EventID   string
+	ShortCode string
+	UserAgent string
+	Referer   string
+	IPAddress string
+	Country   string
+	ClickedAt time.Time
+}
+
+// Time series granularities
+const (
+	GranularityHour  = "hour"
+	GranularityDay   = "day"
+	GranularityMonth = "month"
+)
+
+// StatsQuery selects clicks in [From, To), bucketed by Granularity, with
+// TopLimit entries in each top-N list. Stats are read from the click rollups,
+// so the range is widened to whole hours for hourly series and to whole UTC
+// days otherwise.
+type StatsQuery struct {
+	From        time.Time
+	To          time.Time
+	Granularity string
+	TopLimit    int
+}
+
+type TimeBucket struct {
+	Start        time.Time
+	Clicks       int64
+	UniqueClicks int64
+}
+
+type CountEntry struct {
+	Value string
+	Count int64
+}
+
+type LinkStats struct {
+	TotalClicks       int64
+	UniqueClicks      int64 // Distinct client IPs
+	Series            []TimeBucket
+	TopReferers       []CountEntry
+	TopUserAgents     []CountEntry
+	TopRefererDomains []CountEntry
+	TopDeviceClasses  []CountEntry
+	TopCountries      []CountEntry
+}
+
+type LinkClicks struct {
+	ShortCode    string
+	LongURL      string
+	Clicks       int64
+	UniqueClicks int64
+}
+
+type UserSummary struct {
+	TotalLinks   int64
+	TotalClicks  int64
+	UniqueClicks int64
+	Series       []TimeBucket
+	TopLinks     []LinkClicks
+}
+
+type AnalyticsRepository interface {
+	// InsertCreated stores url_created events, skipping event IDs that are
+	// already stored. It returns the number of events inserted.
+	InsertCreated(ctx context.Context, events []CreatedEvent) (int, error)
+	// InsertClicks stores url_clicked events and adds the new ones to the
+	// click rollups, skipping event IDs that are already stored. It returns
+	// the number of events inserted.
+	InsertClicks(ctx context.Context, events []ClickEvent) (int, error)
+	// LinkOwner returns the user that created shortCode, as recorded by its
+	// url_created event, or ErrNotFound
+	LinkOwner(ctx context.Context, shortCode string) (string, error)
+	LinkStats(ctx context.Context, shortCode string, q StatsQuery) (*LinkStats, error)
+	// UserSummary reports on the links userID created
+	UserSummary(ctx context.Context, userID string, q StatsQuery) (*UserSummary, error)
+	// DeleteLink removes the events recorded for shortCode up to deletedAt
+	// and its click rollups, so a deleted link drops out of its owner's
+	// stats. Events of a link later created with the same short code are
+	// kept.
+	DeleteLink(ctx context.Context, shortCode string, deletedAt time.Time) error
+	// RebuildRollups regenerates the click rollups for [from, to) from the
+	// stored click events. The range is widened to whole UTC days; the
+	// widened range and the number of events read are returned.
+	RebuildRollups(ctx context.Context, from, to time.Time) (time.Time, time.Time, int64, error)
+}
+
+var (
+	_ URLRepository       = (*PostgresURLRepository)(nil)
+	_ OutboxRepository    = (*PostgresURLRepository)(nil)
+	_ UserRepository      = (*PostgresUserRepository)(nil)
+	_ AnalyticsRepository = (*PostgresAnalyticsRepository)(nil)
+
+	_ URLRepository       = (*MemoryURLRepository)(nil)
+	_ OutboxRepository    = (*MemoryURLRepository)(nil)
+	_ UserRepository      = (*MemoryUserRepository)(nil)
+	_ AnalyticsRepository = (*MemoryAnalyticsRepository)(nil)
+)
@@ -0,0 +1,283 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Rollup dimensions. Every bucket also gets a dimensionTotal row with an empty value.
+const (
+	dimensionTotal         = "total"
+	dimensionReferer       = "referer"
+	dimensionUserAgent     = "user_agent"
+	dimensionRefererDomain = "referer_domain"
+	dimensionCountry       = "country"
+	dimensionDeviceClass   = "device_class"
+)
+
+// maxRollupValueLength caps raw referers and user agents so rows stay well
+// within what the rollup primary key index can hold
+const maxRollupValueLength = 512
+
+type rollupKey struct {
+	shortCode   string
+	bucketStart time.Time
+	dimension   string
+	value       string
+}
+
+type visitorKey struct {
+	shortCode   string
+	bucketStart time.Time
+	ip          netip.Addr
+}
+
+// rollupBatch accumulates click counts for clicks_hourly and clicks_daily,
+// and client IPs for click_visitors_hourly and click_visitors_daily
+type rollupBatch struct {
+	hourly         map[rollupKey]int64
+	daily          map[rollupKey]int64
+	hourlyVisitors map[visitorKey]bool
+	dailyVisitors  map[visitorKey]bool
+}
+
+func newRollupBatch() *rollupBatch {
+	return &rollupBatch{
+		hourly:         make(map[rollupKey]int64),
+		daily:          make(map[rollupKey]int64),
+		hourlyVisitors: make(map[visitorKey]bool),
+		dailyVisitors:  make(map[visitorKey]bool),
+	}
+}
+
+// rollupTables names the click count and visitor tables of one bucket size
+type rollupTables struct {
+	clicks   string
+	visitors string
+}
+
+var (
+	hourlyRollups = rollupTables{clicks: "clicks_hourly", visitors: "click_visitors_hourly"}
+	dailyRollups  = rollupTables{clicks: "clicks_daily", visitors: "click_visitors_daily"}
+)
+
+// rollupTableNames lists every table derived from the click events
+var rollupTableNames = []string{
+	hourlyRollups.clicks, dailyRollups.clicks, hourlyRollups.visitors, dailyRollups.visitors,
+}
+
+// add counts one click in every rollup row it belongs to
+func (b *rollupBatch) add(click ClickEvent) {
+	at := click.ClickedAt.UTC()
+	hour := at.Truncate(time.Hour)
+	day := time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, time.UTC)
+
+	dimensions := [][2]string{
+		{dimensionTotal, ""},
+		{dimensionReferer, rawValue(click.Referer)},
+		{dimensionUserAgent, rawValue(click.UserAgent)},
+		{dimensionRefererDomain, refererDomain(click.Referer)},
+		{dimensionCountry, countryValue(click.Country)},
+		{dimensionDeviceClass, deviceClass(click.UserAgent)},
+	}
+	for _, d := range dimensions {
+		b.hourly[rollupKey{click.ShortCode, hour, d[0], d[1]}]++
+		b.daily[rollupKey{click.ShortCode, day, d[0], d[1]}]++
+	}
+
+	// Clicks without an IP are not counted as unique
+	if ip, err := netip.ParseAddr(click.IPAddress); err == nil {
+		b.hourlyVisitors[visitorKey{click.ShortCode, hour, ip}] = true
+		b.dailyVisitors[visitorKey{click.ShortCode, day, ip}] = true
+	}
+}
+
+// apply adds the accumulated counts to the rollup tables inside tx. Tables
+// and rows are always written in the same order so concurrent batches can't
+// deadlock on each other's row locks.
+func (b *rollupBatch) apply(ctx context.Context, tx pgx.Tx) error {
+	tables := []struct {
+		name   string
+		counts map[rollupKey]int64
+	}{
+		{hourlyRollups.clicks, b.hourly},
+		{dailyRollups.clicks, b.daily},
+	}
+
+	for _, t := range tables {
+		table, counts := t.name, t.counts
+		if len(counts) == 0 {
+			continue
+		}
+
+		keys := make([]rollupKey, 0, len(counts))
+		for k := range counts {
+			keys = append(keys, k)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			a, b := keys[i], keys[j]
+			if a.shortCode != b.shortCode {
+				return a.shortCode < b.shortCode
+			}
+			if !a.bucketStart.Equal(b.bucketStart) {
+				return a.bucketStart.Before(b.bucketStart)
+			}
+			if a.dimension != b.dimension {
+				return a.dimension < b.dimension
+			}
+			return a.value < b.value
+		})
+
+		shortCodes := make([]string, 0, len(keys))
+		buckets := make([]time.Time, 0, len(keys))
+		dimensions := make([]string, 0, len(keys))
+		values := make([]string, 0, len(keys))
+		clicks := make([]int64, 0, len(keys))
+		for _, k := range keys {
+			shortCodes = append(shortCodes, k.shortCode)
+			buckets = append(buckets, k.bucketStart)
+			dimensions = append(dimensions, k.dimension)
+			values = append(values, k.value)
+			clicks = append(clicks, counts[k])
+		}
+
+		_, err := tx.Exec(ctx, fmt.Sprintf(`
+			INSERT INTO %[1]s (short_code, bucket_start, dimension, value, clicks)
+			SELECT * FROM unnest($1::text[], $2::timestamptz[], $3::text[], $4::text[], $5::bigint[])
+			ON CONFLICT (short_code, bucket_start, dimension, value)
+			DO UPDATE SET clicks = %[1]s.clicks + EXCLUDED.clicks`, table),
+			shortCodes, buckets, dimensions, values, clicks)
+		if err != nil {
+			return fmt.Errorf("failed to update %s: %w", table, err)
+		}
+	}
+
+	visitorTables := []struct {
+		name     string
+		visitors map[visitorKey]bool
+	}{
+		{hourlyRollups.visitors, b.hourlyVisitors},
+		{dailyRollups.visitors, b.dailyVisitors},
+	}
+	for _, t := range visitorTables {
+		if len(t.visitors) == 0 {
+			continue
+		}
+
+		keys := make([]visitorKey, 0, len(t.visitors))
+		for k := range t.visitors {
+			keys = append(keys, k)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			a, b := keys[i], keys[j]
+			if a.shortCode != b.shortCode {
+				return a.shortCode < b.shortCode
+			}
+			if !a.bucketStart.Equal(b.bucketStart) {
+				return a.bucketStart.Before(b.bucketStart)
+			}
+			return a.ip.Less(b.ip)
+		})
+
+		shortCodes := make([]string, 0, len(keys))
+		buckets := make([]time.Time, 0, len(keys))
+		ips := make([]netip.Addr, 0, len(keys))
+		for _, k := range keys {
+			shortCodes = append(shortCodes, k.shortCode)
+			buckets = append(buckets, k.bucketStart)
+			ips = append(ips, k.ip)
+		}
+
+		_, err := tx.Exec(ctx, fmt.Sprintf(`
+			INSERT INTO %s (short_code, bucket_start, ip_address)
+			SELECT * FROM unnest($1::text[], $2::timestamptz[], $3::inet[])
+			ON CONFLICT DO NOTHING`, t.name),
+			shortCodes, buckets, ips)
+		if err != nil {
+			return fmt.Errorf("failed to update %s: %w", t.name, err)
+		}
+	}
+	return nil
+}
+
+// rawValue reports a raw referer or user agent, cut to
+// maxRollupValueLength, or "(none)" if there is none
+func rawValue(s string) string {
+	if s == "" {
+		return "(none)"
+	}
+	if len(s) > maxRollupValueLength {
+		s = strings.ToValidUTF8(s[:maxRollupValueLength], "")
+	}
+	return s
+}
+
+// refererDomain reduces a referer to its host without a leading "www."
+func refererDomain(referer string) string {
+	if referer == "" {
+		return "(direct)"
+	}
+	u, err := url.Parse(referer)
+	if err != nil || u.Hostname() == "" {
+		return "(unknown)"
+	}
+	return strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+}
+
+func countryValue(country string) string {
+	if country == "" {
+		return "(unknown)"
+	}
+	return strings.ToUpper(country)
+}
+
+// deviceClass buckets a user agent into bot, tablet, mobile or desktop
+func deviceClass(userAgent string) string {
+	ua := strings.ToLower(userAgent)
+	switch {
+	case ua == "":
+		return "(unknown)"
+	case strings.Contains(ua, "bot"), strings.Contains(ua, "crawler"), strings.Contains(ua, "spider"),
+		strings.Contains(ua, "curl/"), strings.Contains(ua, "wget/"):
+		return "bot"
+	case strings.Contains(ua, "ipad"), strings.Contains(ua, "tablet"),
+		strings.Contains(ua, "android") && !strings.Contains(ua, "mobile"):
+		return "tablet"
+	case strings.Contains(ua, "mobi"), strings.Contains(ua, "iphone"), strings.Contains(ua, "android"):
+		return "mobile"
+	default:
+		return "desktop"
+	}
+}
+
+// rollupRange returns the rollup tables that serve q's granularity and q's
+// range widened to whole buckets of them: the hourly tables for hourly
+// series and the daily ones, summed up per month if need be, otherwise
+func rollupRange(q StatsQuery) (rollupTables, time.Time, time.Time) {
+	if truncUnit(q.Granularity) == GranularityHour {
+		from, to := q.From.UTC().Truncate(time.Hour), q.To.UTC().Truncate(time.Hour)
+		if to.Before(q.To) {
+			to = to.Add(time.Hour)
+		}
+		return hourlyRollups, from, to
+	}
+	from, to := wholeDays(q.From, q.To)
+	return dailyRollups, from, to
+}
+
+// wholeDays widens [from, to) to whole UTC days
+func wholeDays(from, to time.Time) (time.Time, time.Time) {
+	from = time.Date(from.UTC().Year(), from.UTC().Month(), from.UTC().Day(), 0, 0, 0, 0, time.UTC)
+	toDay := time.Date(to.UTC().Year(), to.UTC().Month(), to.UTC().Day(), 0, 0, 0, 0, time.UTC)
+	if toDay.Before(to) {
+		toDay = toDay.AddDate(0, 0, 1)
+	}
+	return from, toDay
+}
@@ -0,0 +1,731 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.7
+// 	protoc        v6.32.0
+// source: analytics.proto
+
+package analyticspb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Granularity int32
+
+const (
+	Granularity_GRANULARITY_UNSPECIFIED Granularity = 0 // Treated as DAY
+	Granularity_GRANULARITY_HOUR        Granularity = 1
+	Granularity_GRANULARITY_DAY         Granularity = 2
+	Granularity_GRANULARITY_MONTH       Granularity = 3
+)
+
+// Enum value maps for Granularity.
+var (
+	Granularity_name = map[int32]string{
+		0: "GRANULARITY_UNSPECIFIED",
+		1: "GRANULARITY_HOUR",
+		2: "GRANULARITY_DAY",
+		3: "GRANULARITY_MONTH",
+	}
+	Granularity_value = map[string]int32{
+		"GRANULARITY_UNSPECIFIED": 0,
+		"GRANULARITY_HOUR":        1,
+		"GRANULARITY_DAY":         2,
+		"GRANULARITY_MONTH":       3,
+	}
+)
+
+func (x Granularity) Enum() *Granularity {
+	p := new(Granularity)
+	*p = x
+	return p
+}
+
+func (x Granularity) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Granularity) Descriptor() protoreflect.EnumDescriptor {
+	return file_analytics_proto_enumTypes[0].Descriptor()
+}
+
+func (Granularity) Type() protoreflect.EnumType {
+	return &file_analytics_proto_enumTypes[0]
+}
+
+func (x Granularity) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Granularity.Descriptor instead.
+func (Granularity) EnumDescriptor() ([]byte, []int) {
+	return file_analytics_proto_rawDescGZIP(), []int{0}
+}
+
+type GetLinkStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ShortCode     string                 `protobuf:"bytes,1,opt,name=short_code,json=shortCode,proto3" json:"short_code,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // For authorization check
+	Granularity   Granularity            `protobuf:"varint,3,opt,name=granularity,proto3,enum=analytics.Granularity" json:"granularity,omitempty"`
+	From          string                 `protobuf:"bytes,4,opt,name=from,proto3" json:"from,omitempty"`                          // Optional: ISO 8601 format string, defaults to 30 days ago
+	To            string                 `protobuf:"bytes,5,opt,name=to,proto3" json:"to,omitempty"`                              // Optional: ISO 8601 format string, defaults to now
+	TopLimit      int32                  `protobuf:"varint,6,opt,name=top_limit,json=topLimit,proto3" json:"top_limit,omitempty"` // Optional: size of the top-N lists, defaults to 10
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLinkStatsRequest) Reset() {
+	*x = GetLinkStatsRequest{}
+	mi := &file_analytics_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLinkStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLinkStatsRequest) ProtoMessage() {}
+
+func (x *GetLinkStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLinkStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetLinkStatsRequest) Descriptor() ([]byte, []int) {
+	return file_analytics_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GetLinkStatsRequest) GetShortCode() string {
+	if x != nil {
+		return x.ShortCode
+	}
+	return ""
+}
+
+func (x *GetLinkStatsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GetLinkStatsRequest) GetGranularity() Granularity {
+	if x != nil {
+		return x.Granularity
+	}
+	return Granularity_GRANULARITY_UNSPECIFIED
+}
+
+func (x *GetLinkStatsRequest) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *GetLinkStatsRequest) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+func (x *GetLinkStatsRequest) GetTopLimit() int32 {
+	if x != nil {
+		return x.TopLimit
+	}
+	return 0
+}
+
+type TimeBucket struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BucketStart   string                 `protobuf:"bytes,1,opt,name=bucket_start,json=bucketStart,proto3" json:"bucket_start,omitempty"` // ISO 8601 format string
+	Clicks        int64                  `protobuf:"varint,2,opt,name=clicks,proto3" json:"clicks,omitempty"`
+	UniqueClicks  int64                  `protobuf:"varint,3,opt,name=unique_clicks,json=uniqueClicks,proto3" json:"unique_clicks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TimeBucket) Reset() {
+	*x = TimeBucket{}
+	mi := &file_analytics_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TimeBucket) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TimeBucket) ProtoMessage() {}
+
+func (x *TimeBucket) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TimeBucket.ProtoReflect.Descriptor instead.
+func (*TimeBucket) Descriptor() ([]byte, []int) {
+	return file_analytics_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *TimeBucket) GetBucketStart() string {
+	if x != nil {
+		return x.BucketStart
+	}
+	return ""
+}
+
+func (x *TimeBucket) GetClicks() int64 {
+	if x != nil {
+		return x.Clicks
+	}
+	return 0
+}
+
+func (x *TimeBucket) GetUniqueClicks() int64 {
+	if x != nil {
+		return x.UniqueClicks
+	}
+	return 0
+}
+
+type CountEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Value         string                 `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+	Count         int64                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CountEntry) Reset() {
+	*x = CountEntry{}
+	mi := &file_analytics_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CountEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CountEntry) ProtoMessage() {}
+
+func (x *CountEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CountEntry.ProtoReflect.Descriptor instead.
+func (*CountEntry) Descriptor() ([]byte, []int) {
+	return file_analytics_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CountEntry) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *CountEntry) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type GetLinkStatsResponse struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	ShortCode         string                 `protobuf:"bytes,1,opt,name=short_code,json=shortCode,proto3" json:"short_code,omitempty"`
+	TotalClicks       int64                  `protobuf:"varint,2,opt,name=total_clicks,json=totalClicks,proto3" json:"total_clicks,omitempty"`
+	UniqueClicks      int64                  `protobuf:"varint,3,opt,name=unique_clicks,json=uniqueClicks,proto3" json:"unique_clicks,omitempty"` // Distinct client IPs
+	Series            []*TimeBucket          `protobuf:"bytes,4,rep,name=series,proto3" json:"series,omitempty"`
+	TopReferers       []*CountEntry          `protobuf:"bytes,5,rep,name=top_referers,json=topReferers,proto3" json:"top_referers,omitempty"`         // Cut to 512 bytes; "(none)" for clicks without one
+	TopUserAgents     []*CountEntry          `protobuf:"bytes,6,rep,name=top_user_agents,json=topUserAgents,proto3" json:"top_user_agents,omitempty"` // As top_referers
+	TopCountries      []*CountEntry          `protobuf:"bytes,7,rep,name=top_countries,json=topCountries,proto3" json:"top_countries,omitempty"`
+	TopRefererDomains []*CountEntry          `protobuf:"bytes,8,rep,name=top_referer_domains,json=topRefererDomains,proto3" json:"top_referer_domains,omitempty"` // "(direct)" for clicks without a referer
+	TopDeviceClasses  []*CountEntry          `protobuf:"bytes,9,rep,name=top_device_classes,json=topDeviceClasses,proto3" json:"top_device_classes,omitempty"`    // bot, tablet, mobile or desktop
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *GetLinkStatsResponse) Reset() {
+	*x = GetLinkStatsResponse{}
+	mi := &file_analytics_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLinkStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLinkStatsResponse) ProtoMessage() {}
+
+func (x *GetLinkStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLinkStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetLinkStatsResponse) Descriptor() ([]byte, []int) {
+	return file_analytics_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetLinkStatsResponse) GetShortCode() string {
+	if x != nil {
+		return x.ShortCode
+	}
+	return ""
+}
+
+func (x *GetLinkStatsResponse) GetTotalClicks() int64 {
+	if x != nil {
+		return x.TotalClicks
+	}
+	return 0
+}
+
+func (x *GetLinkStatsResponse) GetUniqueClicks() int64 {
+	if x != nil {
+		return x.UniqueClicks
+	}
+	return 0
+}
+
+func (x *GetLinkStatsResponse) GetSeries() []*TimeBucket {
+	if x != nil {
+		return x.Series
+	}
+	return nil
+}
+
+func (x *GetLinkStatsResponse) GetTopReferers() []*CountEntry {
+	if x != nil {
+		return x.TopReferers
+	}
+	return nil
+}
+
+func (x *GetLinkStatsResponse) GetTopUserAgents() []*CountEntry {
+	if x != nil {
+		return x.TopUserAgents
+	}
+	return nil
+}
+
+func (x *GetLinkStatsResponse) GetTopCountries() []*CountEntry {
+	if x != nil {
+		return x.TopCountries
+	}
+	return nil
+}
+
+func (x *GetLinkStatsResponse) GetTopRefererDomains() []*CountEntry {
+	if x != nil {
+		return x.TopRefererDomains
+	}
+	return nil
+}
+
+func (x *GetLinkStatsResponse) GetTopDeviceClasses() []*CountEntry {
+	if x != nil {
+		return x.TopDeviceClasses
+	}
+	return nil
+}
+
+type GetUserSummaryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Granularity   Granularity            `protobuf:"varint,2,opt,name=granularity,proto3,enum=analytics.Granularity" json:"granularity,omitempty"`
+	From          string                 `protobuf:"bytes,3,opt,name=from,proto3" json:"from,omitempty"`                          // Optional: ISO 8601 format string, defaults to 30 days ago
+	To            string                 `protobuf:"bytes,4,opt,name=to,proto3" json:"to,omitempty"`                              // Optional: ISO 8601 format string, defaults to now
+	TopLimit      int32                  `protobuf:"varint,5,opt,name=top_limit,json=topLimit,proto3" json:"top_limit,omitempty"` // Optional: number of top links, defaults to 10
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUserSummaryRequest) Reset() {
+	*x = GetUserSummaryRequest{}
+	mi := &file_analytics_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserSummaryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserSummaryRequest) ProtoMessage() {}
+
+func (x *GetUserSummaryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserSummaryRequest.ProtoReflect.Descriptor instead.
+func (*GetUserSummaryRequest) Descriptor() ([]byte, []int) {
+	return file_analytics_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetUserSummaryRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GetUserSummaryRequest) GetGranularity() Granularity {
+	if x != nil {
+		return x.Granularity
+	}
+	return Granularity_GRANULARITY_UNSPECIFIED
+}
+
+func (x *GetUserSummaryRequest) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *GetUserSummaryRequest) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+func (x *GetUserSummaryRequest) GetTopLimit() int32 {
+	if x != nil {
+		return x.TopLimit
+	}
+	return 0
+}
+
+type LinkClicks struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ShortCode     string                 `protobuf:"bytes,1,opt,name=short_code,json=shortCode,proto3" json:"short_code,omitempty"`
+	LongUrl       string                 `protobuf:"bytes,2,opt,name=long_url,json=longUrl,proto3" json:"long_url,omitempty"`
+	Clicks        int64                  `protobuf:"varint,3,opt,name=clicks,proto3" json:"clicks,omitempty"`
+	UniqueClicks  int64                  `protobuf:"varint,4,opt,name=unique_clicks,json=uniqueClicks,proto3" json:"unique_clicks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LinkClicks) Reset() {
+	*x = LinkClicks{}
+	mi := &file_analytics_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LinkClicks) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LinkClicks) ProtoMessage() {}
+
+func (x *LinkClicks) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LinkClicks.ProtoReflect.Descriptor instead.
+func (*LinkClicks) Descriptor() ([]byte, []int) {
+	return file_analytics_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *LinkClicks) GetShortCode() string {
+	if x != nil {
+		return x.ShortCode
+	}
+	return ""
+}
+
+func (x *LinkClicks) GetLongUrl() string {
+	if x != nil {
+		return x.LongUrl
+	}
+	return ""
+}
+
+func (x *LinkClicks) GetClicks() int64 {
+	if x != nil {
+		return x.Clicks
+	}
+	return 0
+}
+
+func (x *LinkClicks) GetUniqueClicks() int64 {
+	if x != nil {
+		return x.UniqueClicks
+	}
+	return 0
+}
+
+type GetUserSummaryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TotalLinks    int64                  `protobuf:"varint,1,opt,name=total_links,json=totalLinks,proto3" json:"total_links,omitempty"`
+	TotalClicks   int64                  `protobuf:"varint,2,opt,name=total_clicks,json=totalClicks,proto3" json:"total_clicks,omitempty"`
+	UniqueClicks  int64                  `protobuf:"varint,3,opt,name=unique_clicks,json=uniqueClicks,proto3" json:"unique_clicks,omitempty"`
+	Series        []*TimeBucket          `protobuf:"bytes,4,rep,name=series,proto3" json:"series,omitempty"`
+	TopLinks      []*LinkClicks          `protobuf:"bytes,5,rep,name=top_links,json=topLinks,proto3" json:"top_links,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUserSummaryResponse) Reset() {
+	*x = GetUserSummaryResponse{}
+	mi := &file_analytics_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserSummaryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserSummaryResponse) ProtoMessage() {}
+
+func (x *GetUserSummaryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserSummaryResponse.ProtoReflect.Descriptor instead.
+func (*GetUserSummaryResponse) Descriptor() ([]byte, []int) {
+	return file_analytics_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetUserSummaryResponse) GetTotalLinks() int64 {
+	if x != nil {
+		return x.TotalLinks
+	}
+	return 0
+}
+
+func (x *GetUserSummaryResponse) GetTotalClicks() int64 {
+	if x != nil {
+		return x.TotalClicks
+	}
+	return 0
+}
+
+func (x *GetUserSummaryResponse) GetUniqueClicks() int64 {
+	if x != nil {
+		return x.UniqueClicks
+	}
+	return 0
+}
+
+func (x *GetUserSummaryResponse) GetSeries() []*TimeBucket {
+	if x != nil {
+		return x.Series
+	}
+	return nil
+}
+
+func (x *GetUserSummaryResponse) GetTopLinks() []*LinkClicks {
+	if x != nil {
+		return x.TopLinks
+	}
+	return nil
+}
+
+var File_analytics_proto protoreflect.FileDescriptor
+
+const file_analytics_proto_rawDesc = "" +
+	"\n" +
+	"\x0fanalytics.proto\x12\tanalytics\"\xc8\x01\n" +
+	"\x13GetLinkStatsRequest\x12\x1d\n" +
+	"\n" +
+	"short_code\x18\x01 \x01(\tR\tshortCode\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x128\n" +
+	"\vgranularity\x18\x03 \x01(\x0e2\x16.analytics.GranularityR\vgranularity\x12\x12\n" +
+	"\x04from\x18\x04 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x05 \x01(\tR\x02to\x12\x1b\n" +
+	"\ttop_limit\x18\x06 \x01(\x05R\btopLimit\"l\n" +
+	"\n" +
+	"TimeBucket\x12!\n" +
+	"\fbucket_start\x18\x01 \x01(\tR\vbucketStart\x12\x16\n" +
+	"\x06clicks\x18\x02 \x01(\x03R\x06clicks\x12#\n" +
+	"\runique_clicks\x18\x03 \x01(\x03R\funiqueClicks\"8\n" +
+	"\n" +
+	"CountEntry\x12\x14\n" +
+	"\x05value\x18\x01 \x01(\tR\x05value\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x03R\x05count\"\xed\x03\n" +
+	"\x14GetLinkStatsResponse\x12\x1d\n" +
+	"\n" +
+	"short_code\x18\x01 \x01(\tR\tshortCode\x12!\n" +
+	"\ftotal_clicks\x18\x02 \x01(\x03R\vtotalClicks\x12#\n" +
+	"\runique_clicks\x18\x03 \x01(\x03R\funiqueClicks\x12-\n" +
+	"\x06series\x18\x04 \x03(\v2\x15.analytics.TimeBucketR\x06series\x128\n" +
+	"\ftop_referers\x18\x05 \x03(\v2\x15.analytics.CountEntryR\vtopReferers\x12=\n" +
+	"\x0ftop_user_agents\x18\x06 \x03(\v2\x15.analytics.CountEntryR\rtopUserAgents\x12:\n" +
+	"\rtop_countries\x18\a \x03(\v2\x15.analytics.CountEntryR\ftopCountries\x12E\n" +
+	"\x13top_referer_domains\x18\b \x03(\v2\x15.analytics.CountEntryR\x11topRefererDomains\x12C\n" +
+	"\x12top_device_classes\x18\t \x03(\v2\x15.analytics.CountEntryR\x10topDeviceClasses\"\xab\x01\n" +
+	"\x15GetUserSummaryRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x128\n" +
+	"\vgranularity\x18\x02 \x01(\x0e2\x16.analytics.GranularityR\vgranularity\x12\x12\n" +
+	"\x04from\x18\x03 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x04 \x01(\tR\x02to\x12\x1b\n" +
+	"\ttop_limit\x18\x05 \x01(\x05R\btopLimit\"\x83\x01\n" +
+	"\n" +
+	"LinkClicks\x12\x1d\n" +
+	"\n" +
+	"short_code\x18\x01 \x01(\tR\tshortCode\x12\x19\n" +
+	"\blong_url\x18\x02 \x01(\tR\alongUrl\x12\x16\n" +
+	"\x06clicks\x18\x03 \x01(\x03R\x06clicks\x12#\n" +
+	"\runique_clicks\x18\x04 \x01(\x03R\funiqueClicks\"\xe4\x01\n" +
+	"\x16GetUserSummaryResponse\x12\x1f\n" +
+	"\vtotal_links\x18\x01 \x01(\x03R\n" +
+	"totalLinks\x12!\n" +
+	"\ftotal_clicks\x18\x02 \x01(\x03R\vtotalClicks\x12#\n" +
+	"\runique_clicks\x18\x03 \x01(\x03R\funiqueClicks\x12-\n" +
+	"\x06series\x18\x04 \x03(\v2\x15.analytics.TimeBucketR\x06series\x122\n" +
+	"\ttop_links\x18\x05 \x03(\v2\x15.analytics.LinkClicksR\btopLinks*l\n" +
+	"\vGranularity\x12\x1b\n" +
+	"\x17GRANULARITY_UNSPECIFIED\x10\x00\x12\x14\n" +
+	"\x10GRANULARITY_HOUR\x10\x01\x12\x13\n" +
+	"\x0fGRANULARITY_DAY\x10\x02\x12\x15\n" +
+	"\x11GRANULARITY_MONTH\x10\x032\xba\x01\n" +
+	"\x10AnalyticsService\x12O\n" +
+	"\fGetLinkStats\x12\x1e.analytics.GetLinkStatsRequest\x1a\x1f.analytics.GetLinkStatsResponse\x12U\n" +
+	"\x0eGetUserSummary\x12 .analytics.GetUserSummaryRequest\x1a!.analytics.GetUserSummaryResponseBFZDgithub.com/Farhang-Osman/url-shortener-project/pkg/proto/analyticspbb\x06proto3"
+
+var (
+	file_analytics_proto_rawDescOnce sync.Once
+	file_analytics_proto_rawDescData []byte
+)
+
+func file_analytics_proto_rawDescGZIP() []byte {
+	file_analytics_proto_rawDescOnce.Do(func() {
+		file_analytics_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_analytics_proto_rawDesc), len(file_analytics_proto_rawDesc)))
+	})
+	return file_analytics_proto_rawDescData
+}
+
+var file_analytics_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_analytics_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_analytics_proto_goTypes = []any{
+	(Granularity)(0),               // 0: analytics.Granularity
+	(*GetLinkStatsRequest)(nil),    // 1: analytics.GetLinkStatsRequest
+	(*TimeBucket)(nil),             // 2: analytics.TimeBucket
+	(*CountEntry)(nil),             // 3: analytics.CountEntry
+	(*GetLinkStatsResponse)(nil),   // 4: analytics.GetLinkStatsResponse
+	(*GetUserSummaryRequest)(nil),  // 5: analytics.GetUserSummaryRequest
+	(*LinkClicks)(nil),             // 6: analytics.LinkClicks
+	(*GetUserSummaryResponse)(nil), // 7: analytics.GetUserSummaryResponse
+}
+var file_analytics_proto_depIdxs = []int32{
+	0,  // 0: analytics.GetLinkStatsRequest.granularity:type_name -> analytics.Granularity
+	2,  // 1: analytics.GetLinkStatsResponse.series:type_name -> analytics.TimeBucket
+	3,  // 2: analytics.GetLinkStatsResponse.top_referers:type_name -> analytics.CountEntry
+	3,  // 3: analytics.GetLinkStatsResponse.top_user_agents:type_name -> analytics.CountEntry
+	3,  // 4: analytics.GetLinkStatsResponse.top_countries:type_name -> analytics.CountEntry
+	3,  // 5: analytics.GetLinkStatsResponse.top_referer_domains:type_name -> analytics.CountEntry
+	3,  // 6: analytics.GetLinkStatsResponse.top_device_classes:type_name -> analytics.CountEntry
+	0,  // 7: analytics.GetUserSummaryRequest.granularity:type_name -> analytics.Granularity
+	2,  // 8: analytics.GetUserSummaryResponse.series:type_name -> analytics.TimeBucket
+	6,  // 9: analytics.GetUserSummaryResponse.top_links:type_name -> analytics.LinkClicks
+	1,  // 10: analytics.AnalyticsService.GetLinkStats:input_type -> analytics.GetLinkStatsRequest
+	5,  // 11: analytics.AnalyticsService.GetUserSummary:input_type -> analytics.GetUserSummaryRequest
+	4,  // 12: analytics.AnalyticsService.GetLinkStats:output_type -> analytics.GetLinkStatsResponse
+	7,  // 13: analytics.AnalyticsService.GetUserSummary:output_type -> analytics.GetUserSummaryResponse
+	12, // [12:14] is the sub-list for method output_type
+	10, // [10:12] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
+}
+
+func init() { file_analytics_proto_init() }
+func file_analytics_proto_init() {
+	if File_analytics_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_analytics_proto_rawDesc), len(file_analytics_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_analytics_proto_goTypes,
+		DependencyIndexes: file_analytics_proto_depIdxs,
+		EnumInfos:         file_analytics_proto_enumTypes,
+		MessageInfos:      file_analytics_proto_msgTypes,
+	}.Build()
+	File_analytics_proto = out.File
+	file_analytics_proto_goTypes = nil
+	file_analytics_proto_depIdxs = nil
+}
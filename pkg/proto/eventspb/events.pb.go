@@ -0,0 +1,602 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.7
+// 	protoc        v6.32.0
+// source: events.proto
+
+package eventspb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// EventEnvelope wraps every event published to Kafka. event_type names the
+// payload and version its schema version; consumers reject versions they
+// don't understand instead of misreading them. New payload versions are
+// added as new messages (e.g. URLCreatedV2) and new oneof fields, never by
+// changing an existing message incompatibly.
+type EventEnvelope struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	EventId   string                 `protobuf:"bytes,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`       // Producer-assigned UUID, used for deduplication
+	EventType string                 `protobuf:"bytes,2,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"` // url_created, url_clicked, url_updated or url_deleted
+	Version   uint32                 `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	Timestamp *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"` // When the event was produced
+	// Types that are valid to be assigned to Payload:
+	//
+	//	*EventEnvelope_UrlCreated
+	//	*EventEnvelope_UrlClicked
+	//	*EventEnvelope_UrlUpdated
+	//	*EventEnvelope_UrlDeleted
+	Payload       isEventEnvelope_Payload `protobuf_oneof:"payload"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EventEnvelope) Reset() {
+	*x = EventEnvelope{}
+	mi := &file_events_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EventEnvelope) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EventEnvelope) ProtoMessage() {}
+
+func (x *EventEnvelope) ProtoReflect() protoreflect.Message {
+	mi := &file_events_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EventEnvelope.ProtoReflect.Descriptor instead.
+func (*EventEnvelope) Descriptor() ([]byte, []int) {
+	return file_events_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *EventEnvelope) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *EventEnvelope) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+func (x *EventEnvelope) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *EventEnvelope) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *EventEnvelope) GetPayload() isEventEnvelope_Payload {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *EventEnvelope) GetUrlCreated() *URLCreatedV1 {
+	if x != nil {
+		if x, ok := x.Payload.(*EventEnvelope_UrlCreated); ok {
+			return x.UrlCreated
+		}
+	}
+	return nil
+}
+
+func (x *EventEnvelope) GetUrlClicked() *URLClickedV1 {
+	if x != nil {
+		if x, ok := x.Payload.(*EventEnvelope_UrlClicked); ok {
+			return x.UrlClicked
+		}
+	}
+	return nil
+}
+
+func (x *EventEnvelope) GetUrlUpdated() *URLUpdatedV1 {
+	if x != nil {
+		if x, ok := x.Payload.(*EventEnvelope_UrlUpdated); ok {
+			return x.UrlUpdated
+		}
+	}
+	return nil
+}
+
+func (x *EventEnvelope) GetUrlDeleted() *URLDeletedV1 {
+	if x != nil {
+		if x, ok := x.Payload.(*EventEnvelope_UrlDeleted); ok {
+			return x.UrlDeleted
+		}
+	}
+	return nil
+}
+
+type isEventEnvelope_Payload interface {
+	isEventEnvelope_Payload()
+}
+
+type EventEnvelope_UrlCreated struct {
+	UrlCreated *URLCreatedV1 `protobuf:"bytes,10,opt,name=url_created,json=urlCreated,proto3,oneof"`
+}
+
+type EventEnvelope_UrlClicked struct {
+	UrlClicked *URLClickedV1 `protobuf:"bytes,11,opt,name=url_clicked,json=urlClicked,proto3,oneof"`
+}
+
+type EventEnvelope_UrlUpdated struct {
+	UrlUpdated *URLUpdatedV1 `protobuf:"bytes,12,opt,name=url_updated,json=urlUpdated,proto3,oneof"`
+}
+
+type EventEnvelope_UrlDeleted struct {
+	UrlDeleted *URLDeletedV1 `protobuf:"bytes,13,opt,name=url_deleted,json=urlDeleted,proto3,oneof"`
+}
+
+func (*EventEnvelope_UrlCreated) isEventEnvelope_Payload() {}
+
+func (*EventEnvelope_UrlClicked) isEventEnvelope_Payload() {}
+
+func (*EventEnvelope_UrlUpdated) isEventEnvelope_Payload() {}
+
+func (*EventEnvelope_UrlDeleted) isEventEnvelope_Payload() {}
+
+type URLCreatedV1 struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ShortCode     string                 `protobuf:"bytes,1,opt,name=short_code,json=shortCode,proto3" json:"short_code,omitempty"`
+	LongUrl       string                 `protobuf:"bytes,2,opt,name=long_url,json=longUrl,proto3" json:"long_url,omitempty"`
+	UserId        string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // Empty for anonymous links
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *URLCreatedV1) Reset() {
+	*x = URLCreatedV1{}
+	mi := &file_events_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *URLCreatedV1) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*URLCreatedV1) ProtoMessage() {}
+
+func (x *URLCreatedV1) ProtoReflect() protoreflect.Message {
+	mi := &file_events_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use URLCreatedV1.ProtoReflect.Descriptor instead.
+func (*URLCreatedV1) Descriptor() ([]byte, []int) {
+	return file_events_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *URLCreatedV1) GetShortCode() string {
+	if x != nil {
+		return x.ShortCode
+	}
+	return ""
+}
+
+func (x *URLCreatedV1) GetLongUrl() string {
+	if x != nil {
+		return x.LongUrl
+	}
+	return ""
+}
+
+func (x *URLCreatedV1) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *URLCreatedV1) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type URLClickedV1 struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ShortCode     string                 `protobuf:"bytes,1,opt,name=short_code,json=shortCode,proto3" json:"short_code,omitempty"`
+	ClickedAt     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=clicked_at,json=clickedAt,proto3" json:"clicked_at,omitempty"`
+	UserAgent     string                 `protobuf:"bytes,3,opt,name=user_agent,json=userAgent,proto3" json:"user_agent,omitempty"`
+	Referer       string                 `protobuf:"bytes,4,opt,name=referer,proto3" json:"referer,omitempty"`
+	IpAddress     string                 `protobuf:"bytes,5,opt,name=ip_address,json=ipAddress,proto3" json:"ip_address,omitempty"`
+	Country       string                 `protobuf:"bytes,6,opt,name=country,proto3" json:"country,omitempty"` // ISO 3166-1 alpha-2, empty if unknown
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *URLClickedV1) Reset() {
+	*x = URLClickedV1{}
+	mi := &file_events_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *URLClickedV1) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*URLClickedV1) ProtoMessage() {}
+
+func (x *URLClickedV1) ProtoReflect() protoreflect.Message {
+	mi := &file_events_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use URLClickedV1.ProtoReflect.Descriptor instead.
+func (*URLClickedV1) Descriptor() ([]byte, []int) {
+	return file_events_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *URLClickedV1) GetShortCode() string {
+	if x != nil {
+		return x.ShortCode
+	}
+	return ""
+}
+
+func (x *URLClickedV1) GetClickedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ClickedAt
+	}
+	return nil
+}
+
+func (x *URLClickedV1) GetUserAgent() string {
+	if x != nil {
+		return x.UserAgent
+	}
+	return ""
+}
+
+func (x *URLClickedV1) GetReferer() string {
+	if x != nil {
+		return x.Referer
+	}
+	return ""
+}
+
+func (x *URLClickedV1) GetIpAddress() string {
+	if x != nil {
+		return x.IpAddress
+	}
+	return ""
+}
+
+func (x *URLClickedV1) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+type URLUpdatedV1 struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ShortCode     string                 `protobuf:"bytes,1,opt,name=short_code,json=shortCode,proto3" json:"short_code,omitempty"`
+	LongUrl       string                 `protobuf:"bytes,2,opt,name=long_url,json=longUrl,proto3" json:"long_url,omitempty"` // The new destination
+	UserId        string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Sequence      int64                  `protobuf:"varint,4,opt,name=sequence,proto3" json:"sequence,omitempty"` // Gapless per topic, for cache invalidation
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Disabled      bool                   `protobuf:"varint,6,opt,name=disabled,proto3" json:"disabled,omitempty"` // The owner has deactivated the link
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *URLUpdatedV1) Reset() {
+	*x = URLUpdatedV1{}
+	mi := &file_events_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *URLUpdatedV1) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*URLUpdatedV1) ProtoMessage() {}
+
+func (x *URLUpdatedV1) ProtoReflect() protoreflect.Message {
+	mi := &file_events_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use URLUpdatedV1.ProtoReflect.Descriptor instead.
+func (*URLUpdatedV1) Descriptor() ([]byte, []int) {
+	return file_events_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *URLUpdatedV1) GetShortCode() string {
+	if x != nil {
+		return x.ShortCode
+	}
+	return ""
+}
+
+func (x *URLUpdatedV1) GetLongUrl() string {
+	if x != nil {
+		return x.LongUrl
+	}
+	return ""
+}
+
+func (x *URLUpdatedV1) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *URLUpdatedV1) GetSequence() int64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *URLUpdatedV1) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *URLUpdatedV1) GetDisabled() bool {
+	if x != nil {
+		return x.Disabled
+	}
+	return false
+}
+
+type URLDeletedV1 struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ShortCode     string                 `protobuf:"bytes,1,opt,name=short_code,json=shortCode,proto3" json:"short_code,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Sequence      int64                  `protobuf:"varint,3,opt,name=sequence,proto3" json:"sequence,omitempty"` // Gapless per topic, for cache invalidation
+	DeletedAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=deleted_at,json=deletedAt,proto3" json:"deleted_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *URLDeletedV1) Reset() {
+	*x = URLDeletedV1{}
+	mi := &file_events_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *URLDeletedV1) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*URLDeletedV1) ProtoMessage() {}
+
+func (x *URLDeletedV1) ProtoReflect() protoreflect.Message {
+	mi := &file_events_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use URLDeletedV1.ProtoReflect.Descriptor instead.
+func (*URLDeletedV1) Descriptor() ([]byte, []int) {
+	return file_events_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *URLDeletedV1) GetShortCode() string {
+	if x != nil {
+		return x.ShortCode
+	}
+	return ""
+}
+
+func (x *URLDeletedV1) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *URLDeletedV1) GetSequence() int64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *URLDeletedV1) GetDeletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeletedAt
+	}
+	return nil
+}
+
+var File_events_proto protoreflect.FileDescriptor
+
+const file_events_proto_rawDesc = "" +
+	"\n" +
+	"\fevents.proto\x12\x06events\x1a\x1fgoogle/protobuf/timestamp.proto\"\x8c\x03\n" +
+	"\rEventEnvelope\x12\x19\n" +
+	"\bevent_id\x18\x01 \x01(\tR\aeventId\x12\x1d\n" +
+	"\n" +
+	"event_type\x18\x02 \x01(\tR\teventType\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\rR\aversion\x128\n" +
+	"\ttimestamp\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x127\n" +
+	"\vurl_created\x18\n" +
+	" \x01(\v2\x14.events.URLCreatedV1H\x00R\n" +
+	"urlCreated\x127\n" +
+	"\vurl_clicked\x18\v \x01(\v2\x14.events.URLClickedV1H\x00R\n" +
+	"urlClicked\x127\n" +
+	"\vurl_updated\x18\f \x01(\v2\x14.events.URLUpdatedV1H\x00R\n" +
+	"urlUpdated\x127\n" +
+	"\vurl_deleted\x18\r \x01(\v2\x14.events.URLDeletedV1H\x00R\n" +
+	"urlDeletedB\t\n" +
+	"\apayload\"\x9c\x01\n" +
+	"\fURLCreatedV1\x12\x1d\n" +
+	"\n" +
+	"short_code\x18\x01 \x01(\tR\tshortCode\x12\x19\n" +
+	"\blong_url\x18\x02 \x01(\tR\alongUrl\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\x129\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\xda\x01\n" +
+	"\fURLClickedV1\x12\x1d\n" +
+	"\n" +
+	"short_code\x18\x01 \x01(\tR\tshortCode\x129\n" +
+	"\n" +
+	"clicked_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tclickedAt\x12\x1d\n" +
+	"\n" +
+	"user_agent\x18\x03 \x01(\tR\tuserAgent\x12\x18\n" +
+	"\areferer\x18\x04 \x01(\tR\areferer\x12\x1d\n" +
+	"\n" +
+	"ip_address\x18\x05 \x01(\tR\tipAddress\x12\x18\n" +
+	"\acountry\x18\x06 \x01(\tR\acountry\"\xd4\x01\n" +
+	"\fURLUpdatedV1\x12\x1d\n" +
+	"\n" +
+	"short_code\x18\x01 \x01(\tR\tshortCode\x12\x19\n" +
+	"\blong_url\x18\x02 \x01(\tR\alongUrl\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\x12\x1a\n" +
+	"\bsequence\x18\x04 \x01(\x03R\bsequence\x129\n" +
+	"\n" +
+	"updated_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x1a\n" +
+	"\bdisabled\x18\x06 \x01(\bR\bdisabled\"\x9d\x01\n" +
+	"\fURLDeletedV1\x12\x1d\n" +
+	"\n" +
+	"short_code\x18\x01 \x01(\tR\tshortCode\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x1a\n" +
+	"\bsequence\x18\x03 \x01(\x03R\bsequence\x129\n" +
+	"\n" +
+	"deleted_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tdeletedAtBCZAgithub.com/Farhang-Osman/url-shortener-project/pkg/proto/eventspbb\x06proto3"
+
+var (
+	file_events_proto_rawDescOnce sync.Once
+	file_events_proto_rawDescData []byte
+)
+
+func file_events_proto_rawDescGZIP() []byte {
+	file_events_proto_rawDescOnce.Do(func() {
+		file_events_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_events_proto_rawDesc), len(file_events_proto_rawDesc)))
+	})
+	return file_events_proto_rawDescData
+}
+
+var file_events_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_events_proto_goTypes = []any{
+	(*EventEnvelope)(nil),         // 0: events.EventEnvelope
+	(*URLCreatedV1)(nil),          // 1: events.URLCreatedV1
+	(*URLClickedV1)(nil),          // 2: events.URLClickedV1
+	(*URLUpdatedV1)(nil),          // 3: events.URLUpdatedV1
+	(*URLDeletedV1)(nil),          // 4: events.URLDeletedV1
+	(*timestamppb.Timestamp)(nil), // 5: google.protobuf.Timestamp
+}
+var file_events_proto_depIdxs = []int32{
+	5, // 0: events.EventEnvelope.timestamp:type_name -> google.protobuf.Timestamp
+	1, // 1: events.EventEnvelope.url_created:type_name -> events.URLCreatedV1
+	2, // 2: events.EventEnvelope.url_clicked:type_name -> events.URLClickedV1
+	3, // 3: events.EventEnvelope.url_updated:type_name -> events.URLUpdatedV1
+	4, // 4: events.EventEnvelope.url_deleted:type_name -> events.URLDeletedV1
+	5, // 5: events.URLCreatedV1.created_at:type_name -> google.protobuf.Timestamp
+	5, // 6: events.URLClickedV1.clicked_at:type_name -> google.protobuf.Timestamp
+	5, // 7: events.URLUpdatedV1.updated_at:type_name -> google.protobuf.Timestamp
+	5, // 8: events.URLDeletedV1.deleted_at:type_name -> google.protobuf.Timestamp
+	9, // [9:9] is the sub-list for method output_type
+	9, // [9:9] is the sub-list for method input_type
+	9, // [9:9] is the sub-list for extension type_name
+	9, // [9:9] is the sub-list for extension extendee
+	0, // [0:9] is the sub-list for field type_name
+}
+
+func init() { file_events_proto_init() }
+func file_events_proto_init() {
+	if File_events_proto != nil {
+		return
+	}
+	file_events_proto_msgTypes[0].OneofWrappers = []any{
+		(*EventEnvelope_UrlCreated)(nil),
+		(*EventEnvelope_UrlClicked)(nil),
+		(*EventEnvelope_UrlUpdated)(nil),
+		(*EventEnvelope_UrlDeleted)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_events_proto_rawDesc), len(file_events_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   5,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_events_proto_goTypes,
+		DependencyIndexes: file_events_proto_depIdxs,
+		MessageInfos:      file_events_proto_msgTypes,
+	}.Build()
+	File_events_proto = out.File
+	file_events_proto_goTypes = nil
+	file_events_proto_depIdxs = nil
+}
@@ -21,12 +21,212 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type URLSortField int32
+
+const (
+	URLSortField_URL_SORT_FIELD_UNSPECIFIED   URLSortField = 0 // Treated as CREATED_AT
+	URLSortField_URL_SORT_FIELD_CREATED_AT    URLSortField = 1
+	URLSortField_URL_SORT_FIELD_CLICK_COUNT   URLSortField = 2
+	URLSortField_URL_SORT_FIELD_LAST_ACCESSED URLSortField = 3 // Never accessed links sort as oldest
+)
+
+// Enum value maps for URLSortField.
+var (
+	URLSortField_name = map[int32]string{
+		0: "URL_SORT_FIELD_UNSPECIFIED",
+		1: "URL_SORT_FIELD_CREATED_AT",
+		2: "URL_SORT_FIELD_CLICK_COUNT",
+		3: "URL_SORT_FIELD_LAST_ACCESSED",
+	}
+	URLSortField_value = map[string]int32{
+		"URL_SORT_FIELD_UNSPECIFIED":   0,
+		"URL_SORT_FIELD_CREATED_AT":    1,
+		"URL_SORT_FIELD_CLICK_COUNT":   2,
+		"URL_SORT_FIELD_LAST_ACCESSED": 3,
+	}
+)
+
+func (x URLSortField) Enum() *URLSortField {
+	p := new(URLSortField)
+	*p = x
+	return p
+}
+
+func (x URLSortField) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (URLSortField) Descriptor() protoreflect.EnumDescriptor {
+	return file_shortener_proto_enumTypes[0].Descriptor()
+}
+
+func (URLSortField) Type() protoreflect.EnumType {
+	return &file_shortener_proto_enumTypes[0]
+}
+
+func (x URLSortField) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use URLSortField.Descriptor instead.
+func (URLSortField) EnumDescriptor() ([]byte, []int) {
+	return file_shortener_proto_rawDescGZIP(), []int{0}
+}
+
+type SortOrder int32
+
+const (
+	SortOrder_SORT_ORDER_UNSPECIFIED SortOrder = 0 // Treated as DESC
+	SortOrder_SORT_ORDER_DESC        SortOrder = 1
+	SortOrder_SORT_ORDER_ASC         SortOrder = 2
+)
+
+// Enum value maps for SortOrder.
+var (
+	SortOrder_name = map[int32]string{
+		0: "SORT_ORDER_UNSPECIFIED",
+		1: "SORT_ORDER_DESC",
+		2: "SORT_ORDER_ASC",
+	}
+	SortOrder_value = map[string]int32{
+		"SORT_ORDER_UNSPECIFIED": 0,
+		"SORT_ORDER_DESC":        1,
+		"SORT_ORDER_ASC":         2,
+	}
+)
+
+func (x SortOrder) Enum() *SortOrder {
+	p := new(SortOrder)
+	*p = x
+	return p
+}
+
+func (x SortOrder) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SortOrder) Descriptor() protoreflect.EnumDescriptor {
+	return file_shortener_proto_enumTypes[1].Descriptor()
+}
+
+func (SortOrder) Type() protoreflect.EnumType {
+	return &file_shortener_proto_enumTypes[1]
+}
+
+func (x SortOrder) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SortOrder.Descriptor instead.
+func (SortOrder) EnumDescriptor() ([]byte, []int) {
+	return file_shortener_proto_rawDescGZIP(), []int{1}
+}
+
+type ExpiryFilter int32
+
+const (
+	ExpiryFilter_EXPIRY_FILTER_ANY       ExpiryFilter = 0
+	ExpiryFilter_EXPIRY_FILTER_EXPIRED   ExpiryFilter = 1
+	ExpiryFilter_EXPIRY_FILTER_UNEXPIRED ExpiryFilter = 2
+)
+
+// Enum value maps for ExpiryFilter.
+var (
+	ExpiryFilter_name = map[int32]string{
+		0: "EXPIRY_FILTER_ANY",
+		1: "EXPIRY_FILTER_EXPIRED",
+		2: "EXPIRY_FILTER_UNEXPIRED",
+	}
+	ExpiryFilter_value = map[string]int32{
+		"EXPIRY_FILTER_ANY":       0,
+		"EXPIRY_FILTER_EXPIRED":   1,
+		"EXPIRY_FILTER_UNEXPIRED": 2,
+	}
+)
+
+func (x ExpiryFilter) Enum() *ExpiryFilter {
+	p := new(ExpiryFilter)
+	*p = x
+	return p
+}
+
+func (x ExpiryFilter) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ExpiryFilter) Descriptor() protoreflect.EnumDescriptor {
+	return file_shortener_proto_enumTypes[2].Descriptor()
+}
+
+func (ExpiryFilter) Type() protoreflect.EnumType {
+	return &file_shortener_proto_enumTypes[2]
+}
+
+func (x ExpiryFilter) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ExpiryFilter.Descriptor instead.
+func (ExpiryFilter) EnumDescriptor() ([]byte, []int) {
+	return file_shortener_proto_rawDescGZIP(), []int{2}
+}
+
+type ActiveFilter int32
+
+const (
+	ActiveFilter_ACTIVE_FILTER_ANY      ActiveFilter = 0
+	ActiveFilter_ACTIVE_FILTER_ACTIVE   ActiveFilter = 1
+	ActiveFilter_ACTIVE_FILTER_DISABLED ActiveFilter = 2
+)
+
+// Enum value maps for ActiveFilter.
+var (
+	ActiveFilter_name = map[int32]string{
+		0: "ACTIVE_FILTER_ANY",
+		1: "ACTIVE_FILTER_ACTIVE",
+		2: "ACTIVE_FILTER_DISABLED",
+	}
+	ActiveFilter_value = map[string]int32{
+		"ACTIVE_FILTER_ANY":      0,
+		"ACTIVE_FILTER_ACTIVE":   1,
+		"ACTIVE_FILTER_DISABLED": 2,
+	}
+)
+
+func (x ActiveFilter) Enum() *ActiveFilter {
+	p := new(ActiveFilter)
+	*p = x
+	return p
+}
+
+func (x ActiveFilter) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ActiveFilter) Descriptor() protoreflect.EnumDescriptor {
+	return file_shortener_proto_enumTypes[3].Descriptor()
+}
+
+func (ActiveFilter) Type() protoreflect.EnumType {
+	return &file_shortener_proto_enumTypes[3]
+}
+
+func (x ActiveFilter) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ActiveFilter.Descriptor instead.
+func (ActiveFilter) EnumDescriptor() ([]byte, []int) {
+	return file_shortener_proto_rawDescGZIP(), []int{3}
+}
+
 type ShortenURLRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	LongUrl       string                 `protobuf:"bytes,1,opt,name=long_url,json=longUrl,proto3" json:"long_url,omitempty"`
 	CustomAlias   string                 `protobuf:"bytes,2,opt,name=custom_alias,json=customAlias,proto3" json:"custom_alias,omitempty"` // Optional
 	ExpiresAt     string                 `protobuf:"bytes,3,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`       // Optional: ISO 8601 format string
 	UserId        string                 `protobuf:"bytes,4,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`                // Optional: For authenticated users
+	Tags          []string               `protobuf:"bytes,5,rep,name=tags,proto3" json:"tags,omitempty"`                                  // Optional
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -89,6 +289,13 @@ func (x *ShortenURLRequest) GetUserId() string {
 	return ""
 }
 
+func (x *ShortenURLRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
 type ShortenURLResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	ShortCode     string                 `protobuf:"bytes,1,opt,name=short_code,json=shortCode,proto3" json:"short_code,omitempty"`
@@ -133,6 +340,142 @@ func (x *ShortenURLResponse) GetShortCode() string {
 	return ""
 }
 
+type BulkShortenResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Row           int32                  `protobuf:"varint,1,opt,name=row,proto3" json:"row,omitempty"`                             // Position of the request in the stream, from 0
+	ShortCode     string                 `protobuf:"bytes,2,opt,name=short_code,json=shortCode,proto3" json:"short_code,omitempty"` // Empty on error
+	ShortUrl      string                 `protobuf:"bytes,3,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`    // Empty on error
+	ErrorCode     string                 `protobuf:"bytes,4,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"` // gRPC status code name, e.g. AlreadyExists; empty on success
+	Error         string                 `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BulkShortenResult) Reset() {
+	*x = BulkShortenResult{}
+	mi := &file_shortener_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BulkShortenResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BulkShortenResult) ProtoMessage() {}
+
+func (x *BulkShortenResult) ProtoReflect() protoreflect.Message {
+	mi := &file_shortener_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BulkShortenResult.ProtoReflect.Descriptor instead.
+func (*BulkShortenResult) Descriptor() ([]byte, []int) {
+	return file_shortener_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *BulkShortenResult) GetRow() int32 {
+	if x != nil {
+		return x.Row
+	}
+	return 0
+}
+
+func (x *BulkShortenResult) GetShortCode() string {
+	if x != nil {
+		return x.ShortCode
+	}
+	return ""
+}
+
+func (x *BulkShortenResult) GetShortUrl() string {
+	if x != nil {
+		return x.ShortUrl
+	}
+	return ""
+}
+
+func (x *BulkShortenResult) GetErrorCode() string {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return ""
+}
+
+func (x *BulkShortenResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type BulkShortenURLsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*BulkShortenResult   `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"` // One per request, in stream order
+	Created       int32                  `protobuf:"varint,2,opt,name=created,proto3" json:"created,omitempty"`
+	Failed        int32                  `protobuf:"varint,3,opt,name=failed,proto3" json:"failed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BulkShortenURLsResponse) Reset() {
+	*x = BulkShortenURLsResponse{}
+	mi := &file_shortener_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BulkShortenURLsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BulkShortenURLsResponse) ProtoMessage() {}
+
+func (x *BulkShortenURLsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shortener_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BulkShortenURLsResponse.ProtoReflect.Descriptor instead.
+func (*BulkShortenURLsResponse) Descriptor() ([]byte, []int) {
+	return file_shortener_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *BulkShortenURLsResponse) GetResults() []*BulkShortenResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *BulkShortenURLsResponse) GetCreated() int32 {
+	if x != nil {
+		return x.Created
+	}
+	return 0
+}
+
+func (x *BulkShortenURLsResponse) GetFailed() int32 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
 type GetOriginalURLRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	ShortCode     string                 `protobuf:"bytes,1,opt,name=short_code,json=shortCode,proto3" json:"short_code,omitempty"`
@@ -142,7 +485,7 @@ type GetOriginalURLRequest struct {
 
 func (x *GetOriginalURLRequest) Reset() {
 	*x = GetOriginalURLRequest{}
-	mi := &file_shortener_proto_msgTypes[2]
+	mi := &file_shortener_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -154,7 +497,7 @@ func (x *GetOriginalURLRequest) String() string {
 func (*GetOriginalURLRequest) ProtoMessage() {}
 
 func (x *GetOriginalURLRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_shortener_proto_msgTypes[2]
+	mi := &file_shortener_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -167,7 +510,7 @@ func (x *GetOriginalURLRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetOriginalURLRequest.ProtoReflect.Descriptor instead.
 func (*GetOriginalURLRequest) Descriptor() ([]byte, []int) {
-	return file_shortener_proto_rawDescGZIP(), []int{2}
+	return file_shortener_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *GetOriginalURLRequest) GetShortCode() string {
@@ -179,15 +522,16 @@ func (x *GetOriginalURLRequest) GetShortCode() string {
 
 type GetOriginalURLResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	LongUrl       string                 `protobuf:"bytes,1,opt,name=long_url,json=longUrl,proto3" json:"long_url,omitempty"`
+	LongUrl       string                 `protobuf:"bytes,1,opt,name=long_url,json=longUrl,proto3" json:"long_url,omitempty"`       // Empty if disabled
 	ExpiresAt     string                 `protobuf:"bytes,2,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"` // Optional: ISO 8601 format string
+	Disabled      bool                   `protobuf:"varint,3,opt,name=disabled,proto3" json:"disabled,omitempty"`                   // The owner has deactivated the link
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GetOriginalURLResponse) Reset() {
 	*x = GetOriginalURLResponse{}
-	mi := &file_shortener_proto_msgTypes[3]
+	mi := &file_shortener_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -199,7 +543,7 @@ func (x *GetOriginalURLResponse) String() string {
 func (*GetOriginalURLResponse) ProtoMessage() {}
 
 func (x *GetOriginalURLResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_shortener_proto_msgTypes[3]
+	mi := &file_shortener_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -212,7 +556,7 @@ func (x *GetOriginalURLResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetOriginalURLResponse.ProtoReflect.Descriptor instead.
 func (*GetOriginalURLResponse) Descriptor() ([]byte, []int) {
-	return file_shortener_proto_rawDescGZIP(), []int{3}
+	return file_shortener_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *GetOriginalURLResponse) GetLongUrl() string {
@@ -229,6 +573,13 @@ func (x *GetOriginalURLResponse) GetExpiresAt() string {
 	return ""
 }
 
+func (x *GetOriginalURLResponse) GetDisabled() bool {
+	if x != nil {
+		return x.Disabled
+	}
+	return false
+}
+
 type UpdateURLDestinationRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	ShortCode     string                 `protobuf:"bytes,1,opt,name=short_code,json=shortCode,proto3" json:"short_code,omitempty"`
@@ -240,7 +591,7 @@ type UpdateURLDestinationRequest struct {
 
 func (x *UpdateURLDestinationRequest) Reset() {
 	*x = UpdateURLDestinationRequest{}
-	mi := &file_shortener_proto_msgTypes[4]
+	mi := &file_shortener_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -252,7 +603,7 @@ func (x *UpdateURLDestinationRequest) String() string {
 func (*UpdateURLDestinationRequest) ProtoMessage() {}
 
 func (x *UpdateURLDestinationRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_shortener_proto_msgTypes[4]
+	mi := &file_shortener_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -265,7 +616,7 @@ func (x *UpdateURLDestinationRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateURLDestinationRequest.ProtoReflect.Descriptor instead.
 func (*UpdateURLDestinationRequest) Descriptor() ([]byte, []int) {
-	return file_shortener_proto_rawDescGZIP(), []int{4}
+	return file_shortener_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *UpdateURLDestinationRequest) GetShortCode() string {
@@ -299,7 +650,7 @@ type UpdateURLDestinationResponse struct {
 
 func (x *UpdateURLDestinationResponse) Reset() {
 	*x = UpdateURLDestinationResponse{}
-	mi := &file_shortener_proto_msgTypes[5]
+	mi := &file_shortener_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -311,7 +662,7 @@ func (x *UpdateURLDestinationResponse) String() string {
 func (*UpdateURLDestinationResponse) ProtoMessage() {}
 
 func (x *UpdateURLDestinationResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_shortener_proto_msgTypes[5]
+	mi := &file_shortener_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -324,7 +675,7 @@ func (x *UpdateURLDestinationResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateURLDestinationResponse.ProtoReflect.Descriptor instead.
 func (*UpdateURLDestinationResponse) Descriptor() ([]byte, []int) {
-	return file_shortener_proto_rawDescGZIP(), []int{5}
+	return file_shortener_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *UpdateURLDestinationResponse) GetShortCode() string {
@@ -341,42 +692,960 @@ func (x *UpdateURLDestinationResponse) GetMessage() string {
 	return ""
 }
 
-var File_shortener_proto protoreflect.FileDescriptor
+type URLRevision struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	RevisionId      string                 `protobuf:"bytes,1,opt,name=revision_id,json=revisionId,proto3" json:"revision_id,omitempty"`
+	PreviousLongUrl string                 `protobuf:"bytes,2,opt,name=previous_long_url,json=previousLongUrl,proto3" json:"previous_long_url,omitempty"`
+	NewLongUrl      string                 `protobuf:"bytes,3,opt,name=new_long_url,json=newLongUrl,proto3" json:"new_long_url,omitempty"`
+	ChangedBy       string                 `protobuf:"bytes,4,opt,name=changed_by,json=changedBy,proto3" json:"changed_by,omitempty"` // User ID that made the change
+	ChangedAt       string                 `protobuf:"bytes,5,opt,name=changed_at,json=changedAt,proto3" json:"changed_at,omitempty"` // ISO 8601 format string
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
 
-const file_shortener_proto_rawDesc = "" +
-	"\n" +
-	"\x0fshortener.proto\x12\tshortener\"\x89\x01\n" +
-	"\x11ShortenURLRequest\x12\x19\n" +
-	"\blong_url\x18\x01 \x01(\tR\alongUrl\x12!\n" +
-	"\fcustom_alias\x18\x02 \x01(\tR\vcustomAlias\x12\x1d\n" +
-	"\n" +
-	"expires_at\x18\x03 \x01(\tR\texpiresAt\x12\x17\n" +
-	"\auser_id\x18\x04 \x01(\tR\x06userId\"3\n" +
-	"\x12ShortenURLResponse\x12\x1d\n" +
-	"\n" +
-	"short_code\x18\x01 \x01(\tR\tshortCode\"6\n" +
-	"\x15GetOriginalURLRequest\x12\x1d\n" +
-	"\n" +
-	"short_code\x18\x01 \x01(\tR\tshortCode\"R\n" +
-	"\x16GetOriginalURLResponse\x12\x19\n" +
-	"\blong_url\x18\x01 \x01(\tR\alongUrl\x12\x1d\n" +
-	"\n" +
-	"expires_at\x18\x02 \x01(\tR\texpiresAt\"w\n" +
-	"\x1bUpdateURLDestinationRequest\x12\x1d\n" +
-	"\n" +
-	"short_code\x18\x01 \x01(\tR\tshortCode\x12 \n" +
-	"\fnew_long_url\x18\x02 \x01(\tR\n" +
-	"newLongUrl\x12\x17\n" +
-	"\auser_id\x18\x03 \x01(\tR\x06userId\"W\n" +
-	"\x1cUpdateURLDestinationResponse\x12\x1d\n" +
-	"\n" +
-	"short_code\x18\x01 \x01(\tR\tshortCode\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage2\x9d\x02\n" +
-	"\x10ShortenerService\x12I\n" +
-	"\n" +
-	"ShortenURL\x12\x1c.shortener.ShortenURLRequest\x1a\x1d.shortener.ShortenURLResponse\x12U\n" +
-	"\x0eGetOriginalURL\x12 .shortener.GetOriginalURLRequest\x1a!.shortener.GetOriginalURLResponse\x12g\n" +
-	"\x14UpdateURLDestination\x12&.shortener.UpdateURLDestinationRequest\x1a'.shortener.UpdateURLDestinationResponseBFZDgithub.com/Farhang-Osman/url-shortener-project/pkg/proto/shortenerpbb\x06proto3"
+func (x *URLRevision) Reset() {
+	*x = URLRevision{}
+	mi := &file_shortener_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *URLRevision) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*URLRevision) ProtoMessage() {}
+
+func (x *URLRevision) ProtoReflect() protoreflect.Message {
+	mi := &file_shortener_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use URLRevision.ProtoReflect.Descriptor instead.
+func (*URLRevision) Descriptor() ([]byte, []int) {
+	return file_shortener_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *URLRevision) GetRevisionId() string {
+	if x != nil {
+		return x.RevisionId
+	}
+	return ""
+}
+
+func (x *URLRevision) GetPreviousLongUrl() string {
+	if x != nil {
+		return x.PreviousLongUrl
+	}
+	return ""
+}
+
+func (x *URLRevision) GetNewLongUrl() string {
+	if x != nil {
+		return x.NewLongUrl
+	}
+	return ""
+}
+
+func (x *URLRevision) GetChangedBy() string {
+	if x != nil {
+		return x.ChangedBy
+	}
+	return ""
+}
+
+func (x *URLRevision) GetChangedAt() string {
+	if x != nil {
+		return x.ChangedAt
+	}
+	return ""
+}
+
+type ListURLRevisionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ShortCode     string                 `protobuf:"bytes,1,opt,name=short_code,json=shortCode,proto3" json:"short_code,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // For authorization check
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListURLRevisionsRequest) Reset() {
+	*x = ListURLRevisionsRequest{}
+	mi := &file_shortener_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListURLRevisionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListURLRevisionsRequest) ProtoMessage() {}
+
+func (x *ListURLRevisionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shortener_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListURLRevisionsRequest.ProtoReflect.Descriptor instead.
+func (*ListURLRevisionsRequest) Descriptor() ([]byte, []int) {
+	return file_shortener_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListURLRevisionsRequest) GetShortCode() string {
+	if x != nil {
+		return x.ShortCode
+	}
+	return ""
+}
+
+func (x *ListURLRevisionsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type ListURLRevisionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Revisions     []*URLRevision         `protobuf:"bytes,1,rep,name=revisions,proto3" json:"revisions,omitempty"` // Newest first
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListURLRevisionsResponse) Reset() {
+	*x = ListURLRevisionsResponse{}
+	mi := &file_shortener_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListURLRevisionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListURLRevisionsResponse) ProtoMessage() {}
+
+func (x *ListURLRevisionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shortener_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListURLRevisionsResponse.ProtoReflect.Descriptor instead.
+func (*ListURLRevisionsResponse) Descriptor() ([]byte, []int) {
+	return file_shortener_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListURLRevisionsResponse) GetRevisions() []*URLRevision {
+	if x != nil {
+		return x.Revisions
+	}
+	return nil
+}
+
+type RestoreURLRevisionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ShortCode     string                 `protobuf:"bytes,1,opt,name=short_code,json=shortCode,proto3" json:"short_code,omitempty"`
+	RevisionId    string                 `protobuf:"bytes,2,opt,name=revision_id,json=revisionId,proto3" json:"revision_id,omitempty"` // Destination is restored to this revision's previous_long_url
+	UserId        string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`             // For authorization check
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreURLRevisionRequest) Reset() {
+	*x = RestoreURLRevisionRequest{}
+	mi := &file_shortener_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreURLRevisionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreURLRevisionRequest) ProtoMessage() {}
+
+func (x *RestoreURLRevisionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shortener_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreURLRevisionRequest.ProtoReflect.Descriptor instead.
+func (*RestoreURLRevisionRequest) Descriptor() ([]byte, []int) {
+	return file_shortener_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *RestoreURLRevisionRequest) GetShortCode() string {
+	if x != nil {
+		return x.ShortCode
+	}
+	return ""
+}
+
+func (x *RestoreURLRevisionRequest) GetRevisionId() string {
+	if x != nil {
+		return x.RevisionId
+	}
+	return ""
+}
+
+func (x *RestoreURLRevisionRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type DeleteURLRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ShortCode     string                 `protobuf:"bytes,1,opt,name=short_code,json=shortCode,proto3" json:"short_code,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // For authorization check
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteURLRequest) Reset() {
+	*x = DeleteURLRequest{}
+	mi := &file_shortener_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteURLRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteURLRequest) ProtoMessage() {}
+
+func (x *DeleteURLRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shortener_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteURLRequest.ProtoReflect.Descriptor instead.
+func (*DeleteURLRequest) Descriptor() ([]byte, []int) {
+	return file_shortener_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *DeleteURLRequest) GetShortCode() string {
+	if x != nil {
+		return x.ShortCode
+	}
+	return ""
+}
+
+func (x *DeleteURLRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type DeleteURLResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ShortCode     string                 `protobuf:"bytes,1,opt,name=short_code,json=shortCode,proto3" json:"short_code,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteURLResponse) Reset() {
+	*x = DeleteURLResponse{}
+	mi := &file_shortener_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteURLResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteURLResponse) ProtoMessage() {}
+
+func (x *DeleteURLResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shortener_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteURLResponse.ProtoReflect.Descriptor instead.
+func (*DeleteURLResponse) Descriptor() ([]byte, []int) {
+	return file_shortener_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *DeleteURLResponse) GetShortCode() string {
+	if x != nil {
+		return x.ShortCode
+	}
+	return ""
+}
+
+func (x *DeleteURLResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type SetURLActiveRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ShortCode     string                 `protobuf:"bytes,1,opt,name=short_code,json=shortCode,proto3" json:"short_code,omitempty"`
+	Active        bool                   `protobuf:"varint,2,opt,name=active,proto3" json:"active,omitempty"`              // false disables the link without deleting it
+	UserId        string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // For authorization check
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetURLActiveRequest) Reset() {
+	*x = SetURLActiveRequest{}
+	mi := &file_shortener_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetURLActiveRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetURLActiveRequest) ProtoMessage() {}
+
+func (x *SetURLActiveRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shortener_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetURLActiveRequest.ProtoReflect.Descriptor instead.
+func (*SetURLActiveRequest) Descriptor() ([]byte, []int) {
+	return file_shortener_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *SetURLActiveRequest) GetShortCode() string {
+	if x != nil {
+		return x.ShortCode
+	}
+	return ""
+}
+
+func (x *SetURLActiveRequest) GetActive() bool {
+	if x != nil {
+		return x.Active
+	}
+	return false
+}
+
+func (x *SetURLActiveRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type SetURLActiveResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ShortCode     string                 `protobuf:"bytes,1,opt,name=short_code,json=shortCode,proto3" json:"short_code,omitempty"`
+	Active        bool                   `protobuf:"varint,2,opt,name=active,proto3" json:"active,omitempty"`
+	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetURLActiveResponse) Reset() {
+	*x = SetURLActiveResponse{}
+	mi := &file_shortener_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetURLActiveResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetURLActiveResponse) ProtoMessage() {}
+
+func (x *SetURLActiveResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shortener_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetURLActiveResponse.ProtoReflect.Descriptor instead.
+func (*SetURLActiveResponse) Descriptor() ([]byte, []int) {
+	return file_shortener_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *SetURLActiveResponse) GetShortCode() string {
+	if x != nil {
+		return x.ShortCode
+	}
+	return ""
+}
+
+func (x *SetURLActiveResponse) GetActive() bool {
+	if x != nil {
+		return x.Active
+	}
+	return false
+}
+
+func (x *SetURLActiveResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type URLInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ShortCode     string                 `protobuf:"bytes,1,opt,name=short_code,json=shortCode,proto3" json:"short_code,omitempty"`
+	LongUrl       string                 `protobuf:"bytes,2,opt,name=long_url,json=longUrl,proto3" json:"long_url,omitempty"`
+	Active        bool                   `protobuf:"varint,3,opt,name=active,proto3" json:"active,omitempty"`
+	ExpiresAt     string                 `protobuf:"bytes,4,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"` // Optional: ISO 8601 format string
+	CreatedAt     string                 `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"` // ISO 8601 format string
+	UpdatedAt     string                 `protobuf:"bytes,6,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"` // ISO 8601 format string
+	ClickCount    int64                  `protobuf:"varint,7,opt,name=click_count,json=clickCount,proto3" json:"click_count,omitempty"`
+	LastAccessed  string                 `protobuf:"bytes,8,opt,name=last_accessed,json=lastAccessed,proto3" json:"last_accessed,omitempty"` // Optional: ISO 8601 format string, empty if never accessed
+	UserId        string                 `protobuf:"bytes,9,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`                   // Owner
+	ShortUrl      string                 `protobuf:"bytes,10,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`            // short_code under the public base URL
+	Tags          []string               `protobuf:"bytes,11,rep,name=tags,proto3" json:"tags,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *URLInfo) Reset() {
+	*x = URLInfo{}
+	mi := &file_shortener_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *URLInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*URLInfo) ProtoMessage() {}
+
+func (x *URLInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_shortener_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use URLInfo.ProtoReflect.Descriptor instead.
+func (*URLInfo) Descriptor() ([]byte, []int) {
+	return file_shortener_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *URLInfo) GetShortCode() string {
+	if x != nil {
+		return x.ShortCode
+	}
+	return ""
+}
+
+func (x *URLInfo) GetLongUrl() string {
+	if x != nil {
+		return x.LongUrl
+	}
+	return ""
+}
+
+func (x *URLInfo) GetActive() bool {
+	if x != nil {
+		return x.Active
+	}
+	return false
+}
+
+func (x *URLInfo) GetExpiresAt() string {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return ""
+}
+
+func (x *URLInfo) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *URLInfo) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+func (x *URLInfo) GetClickCount() int64 {
+	if x != nil {
+		return x.ClickCount
+	}
+	return 0
+}
+
+func (x *URLInfo) GetLastAccessed() string {
+	if x != nil {
+		return x.LastAccessed
+	}
+	return ""
+}
+
+func (x *URLInfo) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *URLInfo) GetShortUrl() string {
+	if x != nil {
+		return x.ShortUrl
+	}
+	return ""
+}
+
+func (x *URLInfo) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+type GetURLInfoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ShortCode     string                 `protobuf:"bytes,1,opt,name=short_code,json=shortCode,proto3" json:"short_code,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // For authorization check
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetURLInfoRequest) Reset() {
+	*x = GetURLInfoRequest{}
+	mi := &file_shortener_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetURLInfoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetURLInfoRequest) ProtoMessage() {}
+
+func (x *GetURLInfoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shortener_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetURLInfoRequest.ProtoReflect.Descriptor instead.
+func (*GetURLInfoRequest) Descriptor() ([]byte, []int) {
+	return file_shortener_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *GetURLInfoRequest) GetShortCode() string {
+	if x != nil {
+		return x.ShortCode
+	}
+	return ""
+}
+
+func (x *GetURLInfoRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type ListURLsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`          // Lists this user's links
+	PageSize      int32                  `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`   // Optional: defaults to 20, at most 100
+	PageToken     string                 `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"` // Optional: next_page_token of the previous page
+	SortBy        URLSortField           `protobuf:"varint,4,opt,name=sort_by,json=sortBy,proto3,enum=shortener.URLSortField" json:"sort_by,omitempty"`
+	Order         SortOrder              `protobuf:"varint,5,opt,name=order,proto3,enum=shortener.SortOrder" json:"order,omitempty"`
+	Expiry        ExpiryFilter           `protobuf:"varint,6,opt,name=expiry,proto3,enum=shortener.ExpiryFilter" json:"expiry,omitempty"`
+	Active        ActiveFilter           `protobuf:"varint,7,opt,name=active,proto3,enum=shortener.ActiveFilter" json:"active,omitempty"`
+	CreatedFrom   string                 `protobuf:"bytes,8,opt,name=created_from,json=createdFrom,proto3" json:"created_from,omitempty"` // Optional: ISO 8601 format string, inclusive
+	CreatedTo     string                 `protobuf:"bytes,9,opt,name=created_to,json=createdTo,proto3" json:"created_to,omitempty"`       // Optional: ISO 8601 format string, exclusive
+	Query         string                 `protobuf:"bytes,10,opt,name=query,proto3" json:"query,omitempty"`                               // Optional: case-insensitive substring of long_url or short_code
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListURLsRequest) Reset() {
+	*x = ListURLsRequest{}
+	mi := &file_shortener_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListURLsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListURLsRequest) ProtoMessage() {}
+
+func (x *ListURLsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shortener_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListURLsRequest.ProtoReflect.Descriptor instead.
+func (*ListURLsRequest) Descriptor() ([]byte, []int) {
+	return file_shortener_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ListURLsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ListURLsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListURLsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListURLsRequest) GetSortBy() URLSortField {
+	if x != nil {
+		return x.SortBy
+	}
+	return URLSortField_URL_SORT_FIELD_UNSPECIFIED
+}
+
+func (x *ListURLsRequest) GetOrder() SortOrder {
+	if x != nil {
+		return x.Order
+	}
+	return SortOrder_SORT_ORDER_UNSPECIFIED
+}
+
+func (x *ListURLsRequest) GetExpiry() ExpiryFilter {
+	if x != nil {
+		return x.Expiry
+	}
+	return ExpiryFilter_EXPIRY_FILTER_ANY
+}
+
+func (x *ListURLsRequest) GetActive() ActiveFilter {
+	if x != nil {
+		return x.Active
+	}
+	return ActiveFilter_ACTIVE_FILTER_ANY
+}
+
+func (x *ListURLsRequest) GetCreatedFrom() string {
+	if x != nil {
+		return x.CreatedFrom
+	}
+	return ""
+}
+
+func (x *ListURLsRequest) GetCreatedTo() string {
+	if x != nil {
+		return x.CreatedTo
+	}
+	return ""
+}
+
+func (x *ListURLsRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+type ListURLsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Urls          []*URLInfo             `protobuf:"bytes,1,rep,name=urls,proto3" json:"urls,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // Empty on the last page
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListURLsResponse) Reset() {
+	*x = ListURLsResponse{}
+	mi := &file_shortener_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListURLsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListURLsResponse) ProtoMessage() {}
+
+func (x *ListURLsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shortener_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListURLsResponse.ProtoReflect.Descriptor instead.
+func (*ListURLsResponse) Descriptor() ([]byte, []int) {
+	return file_shortener_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ListURLsResponse) GetUrls() []*URLInfo {
+	if x != nil {
+		return x.Urls
+	}
+	return nil
+}
+
+func (x *ListURLsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+var File_shortener_proto protoreflect.FileDescriptor
+
+const file_shortener_proto_rawDesc = "" +
+	"\n" +
+	"\x0fshortener.proto\x12\tshortener\"\x9d\x01\n" +
+	"\x11ShortenURLRequest\x12\x19\n" +
+	"\blong_url\x18\x01 \x01(\tR\alongUrl\x12!\n" +
+	"\fcustom_alias\x18\x02 \x01(\tR\vcustomAlias\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x03 \x01(\tR\texpiresAt\x12\x17\n" +
+	"\auser_id\x18\x04 \x01(\tR\x06userId\x12\x12\n" +
+	"\x04tags\x18\x05 \x03(\tR\x04tags\"3\n" +
+	"\x12ShortenURLResponse\x12\x1d\n" +
+	"\n" +
+	"short_code\x18\x01 \x01(\tR\tshortCode\"\x96\x01\n" +
+	"\x11BulkShortenResult\x12\x10\n" +
+	"\x03row\x18\x01 \x01(\x05R\x03row\x12\x1d\n" +
+	"\n" +
+	"short_code\x18\x02 \x01(\tR\tshortCode\x12\x1b\n" +
+	"\tshort_url\x18\x03 \x01(\tR\bshortUrl\x12\x1d\n" +
+	"\n" +
+	"error_code\x18\x04 \x01(\tR\terrorCode\x12\x14\n" +
+	"\x05error\x18\x05 \x01(\tR\x05error\"\x83\x01\n" +
+	"\x17BulkShortenURLsResponse\x126\n" +
+	"\aresults\x18\x01 \x03(\v2\x1c.shortener.BulkShortenResultR\aresults\x12\x18\n" +
+	"\acreated\x18\x02 \x01(\x05R\acreated\x12\x16\n" +
+	"\x06failed\x18\x03 \x01(\x05R\x06failed\"6\n" +
+	"\x15GetOriginalURLRequest\x12\x1d\n" +
+	"\n" +
+	"short_code\x18\x01 \x01(\tR\tshortCode\"n\n" +
+	"\x16GetOriginalURLResponse\x12\x19\n" +
+	"\blong_url\x18\x01 \x01(\tR\alongUrl\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x02 \x01(\tR\texpiresAt\x12\x1a\n" +
+	"\bdisabled\x18\x03 \x01(\bR\bdisabled\"w\n" +
+	"\x1bUpdateURLDestinationRequest\x12\x1d\n" +
+	"\n" +
+	"short_code\x18\x01 \x01(\tR\tshortCode\x12 \n" +
+	"\fnew_long_url\x18\x02 \x01(\tR\n" +
+	"newLongUrl\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\"W\n" +
+	"\x1cUpdateURLDestinationResponse\x12\x1d\n" +
+	"\n" +
+	"short_code\x18\x01 \x01(\tR\tshortCode\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"\xba\x01\n" +
+	"\vURLRevision\x12\x1f\n" +
+	"\vrevision_id\x18\x01 \x01(\tR\n" +
+	"revisionId\x12*\n" +
+	"\x11previous_long_url\x18\x02 \x01(\tR\x0fpreviousLongUrl\x12 \n" +
+	"\fnew_long_url\x18\x03 \x01(\tR\n" +
+	"newLongUrl\x12\x1d\n" +
+	"\n" +
+	"changed_by\x18\x04 \x01(\tR\tchangedBy\x12\x1d\n" +
+	"\n" +
+	"changed_at\x18\x05 \x01(\tR\tchangedAt\"Q\n" +
+	"\x17ListURLRevisionsRequest\x12\x1d\n" +
+	"\n" +
+	"short_code\x18\x01 \x01(\tR\tshortCode\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"P\n" +
+	"\x18ListURLRevisionsResponse\x124\n" +
+	"\trevisions\x18\x01 \x03(\v2\x16.shortener.URLRevisionR\trevisions\"t\n" +
+	"\x19RestoreURLRevisionRequest\x12\x1d\n" +
+	"\n" +
+	"short_code\x18\x01 \x01(\tR\tshortCode\x12\x1f\n" +
+	"\vrevision_id\x18\x02 \x01(\tR\n" +
+	"revisionId\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\"J\n" +
+	"\x10DeleteURLRequest\x12\x1d\n" +
+	"\n" +
+	"short_code\x18\x01 \x01(\tR\tshortCode\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"L\n" +
+	"\x11DeleteURLResponse\x12\x1d\n" +
+	"\n" +
+	"short_code\x18\x01 \x01(\tR\tshortCode\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"e\n" +
+	"\x13SetURLActiveRequest\x12\x1d\n" +
+	"\n" +
+	"short_code\x18\x01 \x01(\tR\tshortCode\x12\x16\n" +
+	"\x06active\x18\x02 \x01(\bR\x06active\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\"g\n" +
+	"\x14SetURLActiveResponse\x12\x1d\n" +
+	"\n" +
+	"short_code\x18\x01 \x01(\tR\tshortCode\x12\x16\n" +
+	"\x06active\x18\x02 \x01(\bR\x06active\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\"\xc8\x02\n" +
+	"\aURLInfo\x12\x1d\n" +
+	"\n" +
+	"short_code\x18\x01 \x01(\tR\tshortCode\x12\x19\n" +
+	"\blong_url\x18\x02 \x01(\tR\alongUrl\x12\x16\n" +
+	"\x06active\x18\x03 \x01(\bR\x06active\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x04 \x01(\tR\texpiresAt\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\x06 \x01(\tR\tupdatedAt\x12\x1f\n" +
+	"\vclick_count\x18\a \x01(\x03R\n" +
+	"clickCount\x12#\n" +
+	"\rlast_accessed\x18\b \x01(\tR\flastAccessed\x12\x17\n" +
+	"\auser_id\x18\t \x01(\tR\x06userId\x12\x1b\n" +
+	"\tshort_url\x18\n" +
+	" \x01(\tR\bshortUrl\x12\x12\n" +
+	"\x04tags\x18\v \x03(\tR\x04tags\"K\n" +
+	"\x11GetURLInfoRequest\x12\x1d\n" +
+	"\n" +
+	"short_code\x18\x01 \x01(\tR\tshortCode\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"\xfe\x02\n" +
+	"\x0fListURLsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x03 \x01(\tR\tpageToken\x120\n" +
+	"\asort_by\x18\x04 \x01(\x0e2\x17.shortener.URLSortFieldR\x06sortBy\x12*\n" +
+	"\x05order\x18\x05 \x01(\x0e2\x14.shortener.SortOrderR\x05order\x12/\n" +
+	"\x06expiry\x18\x06 \x01(\x0e2\x17.shortener.ExpiryFilterR\x06expiry\x12/\n" +
+	"\x06active\x18\a \x01(\x0e2\x17.shortener.ActiveFilterR\x06active\x12!\n" +
+	"\fcreated_from\x18\b \x01(\tR\vcreatedFrom\x12\x1d\n" +
+	"\n" +
+	"created_to\x18\t \x01(\tR\tcreatedTo\x12\x14\n" +
+	"\x05query\x18\n" +
+	" \x01(\tR\x05query\"b\n" +
+	"\x10ListURLsResponse\x12&\n" +
+	"\x04urls\x18\x01 \x03(\v2\x12.shortener.URLInfoR\x04urls\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken*\x8f\x01\n" +
+	"\fURLSortField\x12\x1e\n" +
+	"\x1aURL_SORT_FIELD_UNSPECIFIED\x10\x00\x12\x1d\n" +
+	"\x19URL_SORT_FIELD_CREATED_AT\x10\x01\x12\x1e\n" +
+	"\x1aURL_SORT_FIELD_CLICK_COUNT\x10\x02\x12 \n" +
+	"\x1cURL_SORT_FIELD_LAST_ACCESSED\x10\x03*P\n" +
+	"\tSortOrder\x12\x1a\n" +
+	"\x16SORT_ORDER_UNSPECIFIED\x10\x00\x12\x13\n" +
+	"\x0fSORT_ORDER_DESC\x10\x01\x12\x12\n" +
+	"\x0eSORT_ORDER_ASC\x10\x02*]\n" +
+	"\fExpiryFilter\x12\x15\n" +
+	"\x11EXPIRY_FILTER_ANY\x10\x00\x12\x19\n" +
+	"\x15EXPIRY_FILTER_EXPIRED\x10\x01\x12\x1b\n" +
+	"\x17EXPIRY_FILTER_UNEXPIRED\x10\x02*[\n" +
+	"\fActiveFilter\x12\x15\n" +
+	"\x11ACTIVE_FILTER_ANY\x10\x00\x12\x18\n" +
+	"\x14ACTIVE_FILTER_ACTIVE\x10\x01\x12\x1a\n" +
+	"\x16ACTIVE_FILTER_DISABLED\x10\x022\xd4\x06\n" +
+	"\x10ShortenerService\x12I\n" +
+	"\n" +
+	"ShortenURL\x12\x1c.shortener.ShortenURLRequest\x1a\x1d.shortener.ShortenURLResponse\x12U\n" +
+	"\x0eGetOriginalURL\x12 .shortener.GetOriginalURLRequest\x1a!.shortener.GetOriginalURLResponse\x12g\n" +
+	"\x14UpdateURLDestination\x12&.shortener.UpdateURLDestinationRequest\x1a'.shortener.UpdateURLDestinationResponse\x12[\n" +
+	"\x10ListURLRevisions\x12\".shortener.ListURLRevisionsRequest\x1a#.shortener.ListURLRevisionsResponse\x12c\n" +
+	"\x12RestoreURLRevision\x12$.shortener.RestoreURLRevisionRequest\x1a'.shortener.UpdateURLDestinationResponse\x12F\n" +
+	"\tDeleteURL\x12\x1b.shortener.DeleteURLRequest\x1a\x1c.shortener.DeleteURLResponse\x12O\n" +
+	"\fSetURLActive\x12\x1e.shortener.SetURLActiveRequest\x1a\x1f.shortener.SetURLActiveResponse\x12C\n" +
+	"\bListURLs\x12\x1a.shortener.ListURLsRequest\x1a\x1b.shortener.ListURLsResponse\x12>\n" +
+	"\n" +
+	"GetURLInfo\x12\x1c.shortener.GetURLInfoRequest\x1a\x12.shortener.URLInfo\x12U\n" +
+	"\x0fBulkShortenURLs\x12\x1c.shortener.ShortenURLRequest\x1a\".shortener.BulkShortenURLsResponse(\x01BFZDgithub.com/Farhang-Osman/url-shortener-project/pkg/proto/shortenerpbb\x06proto3"
 
 var (
 	file_shortener_proto_rawDescOnce sync.Once
@@ -390,27 +1659,67 @@ func file_shortener_proto_rawDescGZIP() []byte {
 	return file_shortener_proto_rawDescData
 }
 
-var file_shortener_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_shortener_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
+var file_shortener_proto_msgTypes = make([]protoimpl.MessageInfo, 20)
 var file_shortener_proto_goTypes = []any{
-	(*ShortenURLRequest)(nil),            // 0: shortener.ShortenURLRequest
-	(*ShortenURLResponse)(nil),           // 1: shortener.ShortenURLResponse
-	(*GetOriginalURLRequest)(nil),        // 2: shortener.GetOriginalURLRequest
-	(*GetOriginalURLResponse)(nil),       // 3: shortener.GetOriginalURLResponse
-	(*UpdateURLDestinationRequest)(nil),  // 4: shortener.UpdateURLDestinationRequest
-	(*UpdateURLDestinationResponse)(nil), // 5: shortener.UpdateURLDestinationResponse
+	(URLSortField)(0),                    // 0: shortener.URLSortField
+	(SortOrder)(0),                       // 1: shortener.SortOrder
+	(ExpiryFilter)(0),                    // 2: shortener.ExpiryFilter
+	(ActiveFilter)(0),                    // 3: shortener.ActiveFilter
+	(*ShortenURLRequest)(nil),            // 4: shortener.ShortenURLRequest
+	(*ShortenURLResponse)(nil),           // 5: shortener.ShortenURLResponse
+	(*BulkShortenResult)(nil),            // 6: shortener.BulkShortenResult
+	(*BulkShortenURLsResponse)(nil),      // 7: shortener.BulkShortenURLsResponse
+	(*GetOriginalURLRequest)(nil),        // 8: shortener.GetOriginalURLRequest
+	(*GetOriginalURLResponse)(nil),       // 9: shortener.GetOriginalURLResponse
+	(*UpdateURLDestinationRequest)(nil),  // 10: shortener.UpdateURLDestinationRequest
+	(*UpdateURLDestinationResponse)(nil), // 11: shortener.UpdateURLDestinationResponse
+	(*URLRevision)(nil),                  // 12: shortener.URLRevision
+	(*ListURLRevisionsRequest)(nil),      // 13: shortener.ListURLRevisionsRequest
+	(*ListURLRevisionsResponse)(nil),     // 14: shortener.ListURLRevisionsResponse
+	(*RestoreURLRevisionRequest)(nil),    // 15: shortener.RestoreURLRevisionRequest
+	(*DeleteURLRequest)(nil),             // 16: shortener.DeleteURLRequest
+	(*DeleteURLResponse)(nil),            // 17: shortener.DeleteURLResponse
+	(*SetURLActiveRequest)(nil),          // 18: shortener.SetURLActiveRequest
+	(*SetURLActiveResponse)(nil),         // 19: shortener.SetURLActiveResponse
+	(*URLInfo)(nil),                      // 20: shortener.URLInfo
+	(*GetURLInfoRequest)(nil),            // 21: shortener.GetURLInfoRequest
+	(*ListURLsRequest)(nil),              // 22: shortener.ListURLsRequest
+	(*ListURLsResponse)(nil),             // 23: shortener.ListURLsResponse
 }
 var file_shortener_proto_depIdxs = []int32{
-	0, // 0: shortener.ShortenerService.ShortenURL:input_type -> shortener.ShortenURLRequest
-	2, // 1: shortener.ShortenerService.GetOriginalURL:input_type -> shortener.GetOriginalURLRequest
-	4, // 2: shortener.ShortenerService.UpdateURLDestination:input_type -> shortener.UpdateURLDestinationRequest
-	1, // 3: shortener.ShortenerService.ShortenURL:output_type -> shortener.ShortenURLResponse
-	3, // 4: shortener.ShortenerService.GetOriginalURL:output_type -> shortener.GetOriginalURLResponse
-	5, // 5: shortener.ShortenerService.UpdateURLDestination:output_type -> shortener.UpdateURLDestinationResponse
-	3, // [3:6] is the sub-list for method output_type
-	0, // [0:3] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	6,  // 0: shortener.BulkShortenURLsResponse.results:type_name -> shortener.BulkShortenResult
+	12, // 1: shortener.ListURLRevisionsResponse.revisions:type_name -> shortener.URLRevision
+	0,  // 2: shortener.ListURLsRequest.sort_by:type_name -> shortener.URLSortField
+	1,  // 3: shortener.ListURLsRequest.order:type_name -> shortener.SortOrder
+	2,  // 4: shortener.ListURLsRequest.expiry:type_name -> shortener.ExpiryFilter
+	3,  // 5: shortener.ListURLsRequest.active:type_name -> shortener.ActiveFilter
+	20, // 6: shortener.ListURLsResponse.urls:type_name -> shortener.URLInfo
+	4,  // 7: shortener.ShortenerService.ShortenURL:input_type -> shortener.ShortenURLRequest
+	8,  // 8: shortener.ShortenerService.GetOriginalURL:input_type -> shortener.GetOriginalURLRequest
+	10, // 9: shortener.ShortenerService.UpdateURLDestination:input_type -> shortener.UpdateURLDestinationRequest
+	13, // 10: shortener.ShortenerService.ListURLRevisions:input_type -> shortener.ListURLRevisionsRequest
+	15, // 11: shortener.ShortenerService.RestoreURLRevision:input_type -> shortener.RestoreURLRevisionRequest
+	16, // 12: shortener.ShortenerService.DeleteURL:input_type -> shortener.DeleteURLRequest
+	18, // 13: shortener.ShortenerService.SetURLActive:input_type -> shortener.SetURLActiveRequest
+	22, // 14: shortener.ShortenerService.ListURLs:input_type -> shortener.ListURLsRequest
+	21, // 15: shortener.ShortenerService.GetURLInfo:input_type -> shortener.GetURLInfoRequest
+	4,  // 16: shortener.ShortenerService.BulkShortenURLs:input_type -> shortener.ShortenURLRequest
+	5,  // 17: shortener.ShortenerService.ShortenURL:output_type -> shortener.ShortenURLResponse
+	9,  // 18: shortener.ShortenerService.GetOriginalURL:output_type -> shortener.GetOriginalURLResponse
+	11, // 19: shortener.ShortenerService.UpdateURLDestination:output_type -> shortener.UpdateURLDestinationResponse
+	14, // 20: shortener.ShortenerService.ListURLRevisions:output_type -> shortener.ListURLRevisionsResponse
+	11, // 21: shortener.ShortenerService.RestoreURLRevision:output_type -> shortener.UpdateURLDestinationResponse
+	17, // 22: shortener.ShortenerService.DeleteURL:output_type -> shortener.DeleteURLResponse
+	19, // 23: shortener.ShortenerService.SetURLActive:output_type -> shortener.SetURLActiveResponse
+	23, // 24: shortener.ShortenerService.ListURLs:output_type -> shortener.ListURLsResponse
+	20, // 25: shortener.ShortenerService.GetURLInfo:output_type -> shortener.URLInfo
+	7,  // 26: shortener.ShortenerService.BulkShortenURLs:output_type -> shortener.BulkShortenURLsResponse
+	17, // [17:27] is the sub-list for method output_type
+	7,  // [7:17] is the sub-list for method input_type
+	7,  // [7:7] is the sub-list for extension type_name
+	7,  // [7:7] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
 }
 
 func init() { file_shortener_proto_init() }
@@ -423,13 +1732,14 @@ func file_shortener_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_shortener_proto_rawDesc), len(file_shortener_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   6,
+			NumEnums:      4,
+			NumMessages:   20,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_shortener_proto_goTypes,
 		DependencyIndexes: file_shortener_proto_depIdxs,
+		EnumInfos:         file_shortener_proto_enumTypes,
 		MessageInfos:      file_shortener_proto_msgTypes,
 	}.Build()
 	File_shortener_proto = out.File
@@ -22,6 +22,13 @@ const (
 	ShortenerService_ShortenURL_FullMethodName           = "/shortener.ShortenerService/ShortenURL"
 	ShortenerService_GetOriginalURL_FullMethodName       = "/shortener.ShortenerService/GetOriginalURL"
 	ShortenerService_UpdateURLDestination_FullMethodName = "/shortener.ShortenerService/UpdateURLDestination"
+	ShortenerService_ListURLRevisions_FullMethodName     = "/shortener.ShortenerService/ListURLRevisions"
+	ShortenerService_RestoreURLRevision_FullMethodName   = "/shortener.ShortenerService/RestoreURLRevision"
+	ShortenerService_DeleteURL_FullMethodName            = "/shortener.ShortenerService/DeleteURL"
+	ShortenerService_SetURLActive_FullMethodName         = "/shortener.ShortenerService/SetURLActive"
+	ShortenerService_ListURLs_FullMethodName             = "/shortener.ShortenerService/ListURLs"
+	ShortenerService_GetURLInfo_FullMethodName           = "/shortener.ShortenerService/GetURLInfo"
+	ShortenerService_BulkShortenURLs_FullMethodName      = "/shortener.ShortenerService/BulkShortenURLs"
 )
 
 // ShortenerServiceClient is the client API for ShortenerService service.
@@ -31,6 +38,13 @@ type ShortenerServiceClient interface {
 	ShortenURL(ctx context.Context, in *ShortenURLRequest, opts ...grpc.CallOption) (*ShortenURLResponse, error)
 	GetOriginalURL(ctx context.Context, in *GetOriginalURLRequest, opts ...grpc.CallOption) (*GetOriginalURLResponse, error)
 	UpdateURLDestination(ctx context.Context, in *UpdateURLDestinationRequest, opts ...grpc.CallOption) (*UpdateURLDestinationResponse, error)
+	ListURLRevisions(ctx context.Context, in *ListURLRevisionsRequest, opts ...grpc.CallOption) (*ListURLRevisionsResponse, error)
+	RestoreURLRevision(ctx context.Context, in *RestoreURLRevisionRequest, opts ...grpc.CallOption) (*UpdateURLDestinationResponse, error)
+	DeleteURL(ctx context.Context, in *DeleteURLRequest, opts ...grpc.CallOption) (*DeleteURLResponse, error)
+	SetURLActive(ctx context.Context, in *SetURLActiveRequest, opts ...grpc.CallOption) (*SetURLActiveResponse, error)
+	ListURLs(ctx context.Context, in *ListURLsRequest, opts ...grpc.CallOption) (*ListURLsResponse, error)
+	GetURLInfo(ctx context.Context, in *GetURLInfoRequest, opts ...grpc.CallOption) (*URLInfo, error)
+	BulkShortenURLs(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[ShortenURLRequest, BulkShortenURLsResponse], error)
 }
 
 type shortenerServiceClient struct {
@@ -71,6 +85,79 @@ func (c *shortenerServiceClient) UpdateURLDestination(ctx context.Context, in *U
 	return out, nil
 }
 
+func (c *shortenerServiceClient) ListURLRevisions(ctx context.Context, in *ListURLRevisionsRequest, opts ...grpc.CallOption) (*ListURLRevisionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListURLRevisionsResponse)
+	err := c.cc.Invoke(ctx, ShortenerService_ListURLRevisions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortenerServiceClient) RestoreURLRevision(ctx context.Context, in *RestoreURLRevisionRequest, opts ...grpc.CallOption) (*UpdateURLDestinationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateURLDestinationResponse)
+	err := c.cc.Invoke(ctx, ShortenerService_RestoreURLRevision_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortenerServiceClient) DeleteURL(ctx context.Context, in *DeleteURLRequest, opts ...grpc.CallOption) (*DeleteURLResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteURLResponse)
+	err := c.cc.Invoke(ctx, ShortenerService_DeleteURL_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortenerServiceClient) SetURLActive(ctx context.Context, in *SetURLActiveRequest, opts ...grpc.CallOption) (*SetURLActiveResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetURLActiveResponse)
+	err := c.cc.Invoke(ctx, ShortenerService_SetURLActive_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortenerServiceClient) ListURLs(ctx context.Context, in *ListURLsRequest, opts ...grpc.CallOption) (*ListURLsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListURLsResponse)
+	err := c.cc.Invoke(ctx, ShortenerService_ListURLs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortenerServiceClient) GetURLInfo(ctx context.Context, in *GetURLInfoRequest, opts ...grpc.CallOption) (*URLInfo, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(URLInfo)
+	err := c.cc.Invoke(ctx, ShortenerService_GetURLInfo_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortenerServiceClient) BulkShortenURLs(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[ShortenURLRequest, BulkShortenURLsResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ShortenerService_ServiceDesc.Streams[0], ShortenerService_BulkShortenURLs_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ShortenURLRequest, BulkShortenURLsResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ShortenerService_BulkShortenURLsClient = grpc.ClientStreamingClient[ShortenURLRequest, BulkShortenURLsResponse]
+
 // ShortenerServiceServer is the server API for ShortenerService service.
 // All implementations must embed UnimplementedShortenerServiceServer
 // for forward compatibility.
@@ -78,6 +165,13 @@ type ShortenerServiceServer interface {
 	ShortenURL(context.Context, *ShortenURLRequest) (*ShortenURLResponse, error)
 	GetOriginalURL(context.Context, *GetOriginalURLRequest) (*GetOriginalURLResponse, error)
 	UpdateURLDestination(context.Context, *UpdateURLDestinationRequest) (*UpdateURLDestinationResponse, error)
+	ListURLRevisions(context.Context, *ListURLRevisionsRequest) (*ListURLRevisionsResponse, error)
+	RestoreURLRevision(context.Context, *RestoreURLRevisionRequest) (*UpdateURLDestinationResponse, error)
+	DeleteURL(context.Context, *DeleteURLRequest) (*DeleteURLResponse, error)
+	SetURLActive(context.Context, *SetURLActiveRequest) (*SetURLActiveResponse, error)
+	ListURLs(context.Context, *ListURLsRequest) (*ListURLsResponse, error)
+	GetURLInfo(context.Context, *GetURLInfoRequest) (*URLInfo, error)
+	BulkShortenURLs(grpc.ClientStreamingServer[ShortenURLRequest, BulkShortenURLsResponse]) error
 	mustEmbedUnimplementedShortenerServiceServer()
 }
 
@@ -97,6 +191,27 @@ func (UnimplementedShortenerServiceServer) GetOriginalURL(context.Context, *GetO
 func (UnimplementedShortenerServiceServer) UpdateURLDestination(context.Context, *UpdateURLDestinationRequest) (*UpdateURLDestinationResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdateURLDestination not implemented")
 }
+func (UnimplementedShortenerServiceServer) ListURLRevisions(context.Context, *ListURLRevisionsRequest) (*ListURLRevisionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListURLRevisions not implemented")
+}
+func (UnimplementedShortenerServiceServer) RestoreURLRevision(context.Context, *RestoreURLRevisionRequest) (*UpdateURLDestinationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreURLRevision not implemented")
+}
+func (UnimplementedShortenerServiceServer) DeleteURL(context.Context, *DeleteURLRequest) (*DeleteURLResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteURL not implemented")
+}
+func (UnimplementedShortenerServiceServer) SetURLActive(context.Context, *SetURLActiveRequest) (*SetURLActiveResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetURLActive not implemented")
+}
+func (UnimplementedShortenerServiceServer) ListURLs(context.Context, *ListURLsRequest) (*ListURLsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListURLs not implemented")
+}
+func (UnimplementedShortenerServiceServer) GetURLInfo(context.Context, *GetURLInfoRequest) (*URLInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetURLInfo not implemented")
+}
+func (UnimplementedShortenerServiceServer) BulkShortenURLs(grpc.ClientStreamingServer[ShortenURLRequest, BulkShortenURLsResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method BulkShortenURLs not implemented")
+}
 func (UnimplementedShortenerServiceServer) mustEmbedUnimplementedShortenerServiceServer() {}
 func (UnimplementedShortenerServiceServer) testEmbeddedByValue()                          {}
 
@@ -172,6 +287,121 @@ func _ShortenerService_UpdateURLDestination_Handler(srv interface{}, ctx context
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ShortenerService_ListURLRevisions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListURLRevisionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServiceServer).ListURLRevisions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortenerService_ListURLRevisions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServiceServer).ListURLRevisions(ctx, req.(*ListURLRevisionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShortenerService_RestoreURLRevision_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreURLRevisionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServiceServer).RestoreURLRevision(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortenerService_RestoreURLRevision_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServiceServer).RestoreURLRevision(ctx, req.(*RestoreURLRevisionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShortenerService_DeleteURL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteURLRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServiceServer).DeleteURL(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortenerService_DeleteURL_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServiceServer).DeleteURL(ctx, req.(*DeleteURLRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShortenerService_SetURLActive_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetURLActiveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServiceServer).SetURLActive(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortenerService_SetURLActive_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServiceServer).SetURLActive(ctx, req.(*SetURLActiveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShortenerService_ListURLs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListURLsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServiceServer).ListURLs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortenerService_ListURLs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServiceServer).ListURLs(ctx, req.(*ListURLsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShortenerService_GetURLInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetURLInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServiceServer).GetURLInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortenerService_GetURLInfo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServiceServer).GetURLInfo(ctx, req.(*GetURLInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShortenerService_BulkShortenURLs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(ShortenerServiceServer).BulkShortenURLs(&grpc.GenericServerStream[ShortenURLRequest, BulkShortenURLsResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ShortenerService_BulkShortenURLsServer = grpc.ClientStreamingServer[ShortenURLRequest, BulkShortenURLsResponse]
+
 // ShortenerService_ServiceDesc is the grpc.ServiceDesc for ShortenerService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -191,7 +421,37 @@ var ShortenerService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "UpdateURLDestination",
 			Handler:    _ShortenerService_UpdateURLDestination_Handler,
 		},
+		{
+			MethodName: "ListURLRevisions",
+			Handler:    _ShortenerService_ListURLRevisions_Handler,
+		},
+		{
+			MethodName: "RestoreURLRevision",
+			Handler:    _ShortenerService_RestoreURLRevision_Handler,
+		},
+		{
+			MethodName: "DeleteURL",
+			Handler:    _ShortenerService_DeleteURL_Handler,
+		},
+		{
+			MethodName: "SetURLActive",
+			Handler:    _ShortenerService_SetURLActive_Handler,
+		},
+		{
+			MethodName: "ListURLs",
+			Handler:    _ShortenerService_ListURLs_Handler,
+		},
+		{
+			MethodName: "GetURLInfo",
+			Handler:    _ShortenerService_GetURLInfo_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "BulkShortenURLs",
+			Handler:       _ShortenerService_BulkShortenURLs_Handler,
+			ClientStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "shortener.proto",
 }
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"container/list"
+	"context"
+	"expvar"
+	"hash/fnv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	cacheShards      = 16
+	cacheCapacity    = 100000 // entries across all shards
+	cacheTTL         = 5 * time.Minute
+	cacheNegativeTTL = 10 * time.Second
+	cacheLookupLimit = time.Second
+)
+
+var (
+	cacheHits   = expvar.NewInt("url_cache_hits")
+	cacheMisses = expvar.NewInt("url_cache_misses")
+)
+
+// cachedURL is the result of resolving a short code. Negative results (not
+// found, expired) carry the error returned by the shortener service.
+type cachedURL struct {
+	longURL   string
+	expiresAt time.Time // zero if the link never expires
+	disabled  bool      // deactivated by its owner
+	err       error
+}
+
+type cacheEntry struct {
+	key       string
+	value     cachedURL
+	staleAt   time.Time
+	listEntry *list.Element
+}
+
+// cacheShard is a fixed capacity LRU protected by its own lock
+type cacheShard struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[string]*cacheEntry
+	order    *list.List // front is most recently used
+}
+
+func (s *cacheShard) get(key string, now time.Time) (cachedURL, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.entries[key]
+	if !ok {
+		return cachedURL{}, false
+	}
+	if !now.Before(e.staleAt) {
+		s.removeLocked(e)
+		return cachedURL{}, false
+	}
+	s.order.MoveToFront(e.listEntry)
+	return e.value, true
+}
+
+func (s *cacheShard) set(key string, value cachedURL, staleAt time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if e, ok := s.entries[key]; ok {
+		e.value = value
+		e.staleAt = staleAt
+		s.order.MoveToFront(e.listEntry)
+		return
+	}
+
+	e := &cacheEntry{key: key, value: value, staleAt: staleAt}
+	e.listEntry = s.order.PushFront(e)
+	s.entries[key] = e
+
+	for len(s.entries) > s.capacity {
+		s.removeLocked(s.order.Back().Value.(*cacheEntry))
+	}
+}
+
+func (s *cacheShard) delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if e, ok := s.entries[key]; ok {
+		s.removeLocked(e)
+	}
+}
+
+func (s *cacheShard) purge() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries = make(map[string]*cacheEntry)
+	s.order.Init()
+}
+
+func (s *cacheShard) removeLocked(e *cacheEntry) {
+	s.order.Remove(e.listEntry)
+	delete(s.entries, e.key)
+}
+
+// urlCache maps short codes to their destinations. Entries live for at most
+// cacheTTL and never past the link's own expiry; negative results are kept
+// for cacheNegativeTTL. Concurrent misses for the same code share one lookup.
+type urlCache struct {
+	shards []*cacheShard
+	flight singleflight.Group
+
+	// generation is bumped by every eviction so a lookup that raced with an
+	// invalidation doesn't store the destination it read before the change
+	generation atomic.Uint64
+}
+
+func newURLCache() *urlCache {
+	c := &urlCache{shards: make([]*cacheShard, cacheShards)}
+	for i := range c.shards {
+		c.shards[i] = &cacheShard{
+			capacity: cacheCapacity / cacheShards,
+			entries:  make(map[string]*cacheEntry),
+			order:    list.New(),
+		}
+	}
+	return c
+}
+
+func (c *urlCache) shard(key string) *cacheShard {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return c.shards[h.Sum32()%uint32(len(c.shards))]
+}
+
+// Get returns the cached result for shortCode, calling fetch on a miss.
+// Results for which cacheable returns false (e.g. transport errors) are
+// handed back to the caller but not stored.
+func (c *urlCache) Get(shortCode string, fetch func(ctx context.Context) cachedURL, cacheable func(error) bool) cachedURL {
+	now := time.Now()
+	if v, ok := c.shard(shortCode).get(shortCode, now); ok {
+		cacheHits.Add(1)
+		return v
+	}
+	cacheMisses.Add(1)
+
+	v, _, _ := c.flight.Do(shortCode, func() (interface{}, error) {
+		// Not tied to any single caller's request so one cancelled
+		// redirect doesn't fail everyone waiting on the same code
+		ctx, cancel := context.WithTimeout(context.Background(), cacheLookupLimit)
+		defer cancel()
+
+		generation := c.generation.Load()
+		result := fetch(ctx)
+		if result.err != nil && !cacheable(result.err) {
+			return result, nil
+		}
+		if c.generation.Load() != generation {
+			return result, nil
+		}
+
+		now := time.Now()
+		staleAt := now.Add(cacheTTL)
+		if result.err != nil {
+			staleAt = now.Add(cacheNegativeTTL)
+		} else if !result.expiresAt.IsZero() && result.expiresAt.Before(staleAt) {
+			staleAt = result.expiresAt
+		}
+		c.shard(shortCode).set(shortCode, result, staleAt)
+		return result, nil
+	})
+	return v.(cachedURL)
+}
+
+// Delete evicts a single short code
+func (c *urlCache) Delete(shortCode string) {
+	c.generation.Add(1)
+	c.shard(shortCode).delete(shortCode)
+}
+
+// Purge evicts every entry
+func (c *urlCache) Purge() {
+	c.generation.Add(1)
+	for _, s := range c.shards {
+		s.purge()
+	}
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"expvar"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+
+	"github.com/Farhang-Osman/url-shortener-project/common/events"
+	eventspb "github.com/Farhang-Osman/url-shortener-project/pkg/proto/eventspb"
+)
+
+const (
+	clickTopic          = "url-click-events"
+	clickBufferSize     = 10000
+	clickBatchSize      = 100
+	clickFlushInterval  = 500 * time.Millisecond
+	clickWriteTimeout   = 5 * time.Second
+	clickShutdownBudget = 5 * time.Second
+)
+
+var (
+	clickEventsPublished = expvar.NewInt("click_events_published")
+	clickEventsDropped   = expvar.NewInt("click_events_dropped")
+)
+
+// clickPublisher sends click events to Kafka off the request path. Events are
+// queued on a bounded buffer and written in batches by a background goroutine;
+// when the buffer is full (e.g. during a broker outage) new events are dropped
+// and counted rather than slowing down the redirect.
+type clickPublisher struct {
+	writer *kafka.Writer
+	events chan *eventspb.EventEnvelope
+	done   chan struct{}
+	wg     sync.WaitGroup
+}
+
+func newClickPublisher(brokers []string) *clickPublisher {
+	p := &clickPublisher{
+		writer: &kafka.Writer{
+			Addr:         kafka.TCP(brokers...),
+			Topic:        clickTopic,
+			Balancer:     &kafka.Hash{},
+			BatchSize:    clickBatchSize,
+			BatchTimeout: 10 * time.Millisecond,
+			WriteTimeout: clickWriteTimeout,
+		},
+		events: make(chan *eventspb.EventEnvelope, clickBufferSize),
+		done:   make(chan struct{}),
+	}
+	p.wg.Add(1)
+	go p.run()
+	return p
+}
+
+// Publish queues a click event. It never blocks.
+func (p *clickPublisher) Publish(click *eventspb.URLClickedV1) {
+	select {
+	case p.events <- events.NewURLClicked(click):
+	default:
+		clickEventsDropped.Add(1)
+	}
+}
+
+// Close flushes queued events, giving up after a short grace period, and
+// closes the Kafka writer.
+func (p *clickPublisher) Close() {
+	close(p.done)
+	p.wg.Wait()
+	p.writer.Close()
+}
+
+func (p *clickPublisher) run() {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(clickFlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]kafka.Message, 0, clickBatchSize)
+	flush := func(timeout time.Duration) {
+		if len(batch) == 0 {
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		if err := p.writer.WriteMessages(ctx, batch...); err != nil {
+			log.Printf("Warning: dropping %d click events, failed to publish to Kafka: %v", len(batch), err)
+			clickEventsDropped.Add(int64(len(batch)))
+		} else {
+			clickEventsPublished.Add(int64(len(batch)))
+		}
+		batch = batch[:0]
+	}
+
+	add := func(env *eventspb.EventEnvelope) {
+		value, err := events.Marshal(env)
+		if err != nil {
+			log.Printf("Warning: failed to marshal click event: %v", err)
+			clickEventsDropped.Add(1)
+			return
+		}
+		batch = append(batch, kafka.Message{
+			Key:     []byte(env.GetUrlClicked().GetShortCode()),
+			Value:   value,
+			Headers: []kafka.Header{{Key: events.HeaderContentType, Value: []byte(events.ContentTypeProtobuf)}},
+		})
+	}
+
+	for {
+		select {
+		case event := <-p.events:
+			add(event)
+			if len(batch) >= clickBatchSize {
+				flush(clickWriteTimeout)
+			}
+		case <-ticker.C:
+			flush(clickWriteTimeout)
+		case <-p.done:
+			for {
+				select {
+				case event := <-p.events:
+					add(event)
+				default:
+					flush(clickShutdownBudget)
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,20 @@
+package main
+
+import "github.com/Farhang-Osman/url-shortener-project/common/config"
+
+// Config is loaded from the environment, flags and an optional YAML file;
+// see package config
+type Config struct {
+	ListenAddr           string       `yaml:"listen_addr" env:"LISTEN_ADDR" flag:"listen" default:":8081" usage:"HTTP listen address"`
+	DebugListenAddr      string       `yaml:"debug_listen_addr" env:"DEBUG_LISTEN_ADDR" flag:"debug-listen" default:"localhost:9081" usage:"internal HTTP listen address for /debug/vars, empty to disable"`
+	ShortenerServiceAddr string       `yaml:"shortener_service_addr" env:"SHORTENER_SERVICE_ADDR" flag:"shortener-service-addr" default:"localhost:50052" usage:"shortener-service gRPC address"`
+	Kafka                config.Kafka `yaml:"kafka"`
+	// IPs or CIDRs whose X-Forwarded-For header is trusted; when empty,
+	// X-Forwarded-For is ignored
+	TrustedProxies []string `yaml:"trusted_proxies" env:"TRUSTED_PROXIES" flag:"trusted-proxies" usage:"comma separated IPs or CIDRs of trusted reverse proxies"`
+}
+
+func (c *Config) Validate() error {
+	_, err := parseTrustedProxies(c.TrustedProxies)
+	return err
+}
@@ -3,15 +3,26 @@ module github.com/Farhang-Osman/url-shortener-project/redirect-service
 go 1.24.6
 
 require (
+	github.com/Farhang-Osman/url-shortener-project v0.0.0-20250909120117-2100e84036d8
 	github.com/Farhang-Osman/url-shortener-project/pkg/proto v0.0.0-20250822173454-061879e34199
 	github.com/gorilla/mux v1.8.1
+	github.com/segmentio/kafka-go v0.4.49
+	golang.org/x/sync v0.16.0
 	google.golang.org/grpc v1.75.0
+	google.golang.org/protobuf v1.36.8
 )
 
 require (
+	github.com/klauspost/compress v1.18.0 // indirect
+	github.com/pierrec/lz4/v4 v4.1.22 // indirect
 	golang.org/x/net v0.41.0 // indirect
 	golang.org/x/sys v0.33.0 // indirect
-	golang.org/x/text v0.26.0 // indirect
+	golang.org/x/text v0.27.0 // indirect
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20250707201910-8d1bb00bc6a7 // indirect
-	google.golang.org/protobuf v1.36.8 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace (
+	github.com/Farhang-Osman/url-shortener-project => ../
+	github.com/Farhang-Osman/url-shortener-project/pkg/proto => ../pkg/proto
 )
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"expvar"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+
+	"github.com/Farhang-Osman/url-shortener-project/common/events"
+	eventspb "github.com/Farhang-Osman/url-shortener-project/pkg/proto/eventspb"
+)
+
+// Topics published by shortener-service whenever a link's destination changes
+// or the link goes away. Each has a single partition carrying a gapless
+// sequence number, which main checks before the listener starts.
+const (
+	updatedTopic = "url-updated-events"
+	deletedTopic = "url-deleted-events"
+)
+
+var (
+	cacheInvalidations = expvar.NewInt("url_cache_invalidations")
+	cacheFullFlushes   = expvar.NewInt("url_cache_full_flushes")
+)
+
+// invalidationTarget extracts the short code and sequence number from a
+// url-updated or url-deleted message
+func invalidationTarget(topic string, msg kafka.Message) (string, int64, error) {
+	legacyType := events.TypeURLUpdated
+	if topic == deletedTopic {
+		legacyType = events.TypeURLDeleted
+	}
+
+	env, err := events.Decode(msg.Value, headerValue(msg, events.HeaderContentType), legacyType)
+	if err != nil {
+		return "", 0, err
+	}
+
+	switch p := env.GetPayload().(type) {
+	case *eventspb.EventEnvelope_UrlUpdated:
+		return p.UrlUpdated.GetShortCode(), p.UrlUpdated.GetSequence(), nil
+	case *eventspb.EventEnvelope_UrlDeleted:
+		return p.UrlDeleted.GetShortCode(), p.UrlDeleted.GetSequence(), nil
+	default:
+		return "", 0, fmt.Errorf("unexpected %s event on %s", env.GetEventType(), topic)
+	}
+}
+
+func headerValue(msg kafka.Message, key string) string {
+	for _, h := range msg.Headers {
+		if h.Key == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+// invalidationListener evicts cache entries as links change. Every replica
+// reads the topics independently (no consumer group) starting from the
+// newest offset, since its cache holds nothing older. If it notices a gap in
+// the sequence, or loses its connection, it can no longer tell what it
+// missed and flushes the whole cache.
+type invalidationListener struct {
+	cache  *urlCache
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func newInvalidationListener(brokers []string, cache *urlCache) *invalidationListener {
+	ctx, cancel := context.WithCancel(context.Background())
+	l := &invalidationListener{cache: cache, cancel: cancel}
+
+	for _, topic := range []string{updatedTopic, deletedTopic} {
+		reader := kafka.NewReader(kafka.ReaderConfig{
+			Brokers:   brokers,
+			Topic:     topic,
+			Partition: 0,
+			MaxWait:   500 * time.Millisecond,
+		})
+
+		l.wg.Add(1)
+		go l.listen(ctx, topic, reader)
+	}
+	return l
+}
+
+// Close stops all readers
+func (l *invalidationListener) Close() {
+	l.cancel()
+	l.wg.Wait()
+}
+
+func (l *invalidationListener) listen(ctx context.Context, topic string, reader *kafka.Reader) {
+	defer l.wg.Done()
+	defer reader.Close()
+
+	if err := reader.SetOffset(kafka.LastOffset); err != nil {
+		log.Printf("Warning: failed to seek %s to latest offset: %v", topic, err)
+	}
+
+	var lastSeq int64
+	for {
+		msg, err := reader.ReadMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("Error reading %s, flushing URL cache: %v", topic, err)
+			l.flush()
+			lastSeq = 0
+			time.Sleep(5 * time.Second) // Wait before retrying
+			continue
+		}
+
+		shortCode, seq, err := invalidationTarget(topic, msg)
+		if err != nil || shortCode == "" {
+			log.Printf("Error decoding %s event at offset %d, flushing URL cache: %v", topic, msg.Offset, err)
+			l.flush()
+			continue
+		}
+
+		l.cache.Delete(shortCode)
+		cacheInvalidations.Add(1)
+
+		if lastSeq != 0 && seq > lastSeq+1 {
+			log.Printf("Gap in %s sequence (%d -> %d), flushing URL cache", topic, lastSeq, seq)
+			l.flush()
+		}
+		if seq > lastSeq {
+			lastSeq = seq
+		}
+	}
+}
+
+func (l *invalidationListener) flush() {
+	l.cache.Purge()
+	cacheFullFlushes.Add(1)
+}
@@ -2,68 +2,168 @@ package main
 
 import (
 	"context"
+	"expvar"
 	"log"
 	"net/http"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/gorilla/mux"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
+	"github.com/Farhang-Osman/url-shortener-project/common/config"
+	"github.com/Farhang-Osman/url-shortener-project/common/eventbus"
+	eventspb "github.com/Farhang-Osman/url-shortener-project/pkg/proto/eventspb"
 	shortenerpb "github.com/Farhang-Osman/url-shortener-project/pkg/proto/shortenerpb" // IMPORTANT: Use your main module path
 )
 
 const (
-	shortenerServiceAddr = "localhost:50052"
+	countryHeader = "CF-IPCountry" // ISO 3166-1 alpha-2 code set by the CDN in front of us
+
+	// How long in-flight redirects get to finish after SIGTERM
+	shutdownTimeout = 10 * time.Second
 )
 
+// isNegativeResult reports whether a lookup error is a definite answer about
+// the short code that is safe to cache, as opposed to a transient failure
+func isNegativeResult(err error) bool {
+	switch status.Code(err) {
+	case codes.NotFound, codes.FailedPrecondition:
+		return true
+	default:
+		return false
+	}
+}
+
 func main() {
+	var cfg Config
+	if args := config.MustLoad(&cfg, "redirect-service"); len(args) > 0 {
+		log.Fatalf("unexpected arguments %q", args)
+	}
+
 	// Set up a connection to the Shortener Service
-	conn, err := grpc.Dial(shortenerServiceAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := grpc.Dial(cfg.ShortenerServiceAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
 	if err != nil {
 		log.Fatalf("did not connect to shortener service: %v", err)
 	}
 	defer conn.Close()
 	shortenerClient := shortenerpb.NewShortenerServiceClient(conn)
 
+	clicks := newClickPublisher(cfg.Kafka.Brokers)
+
+	proxies, _ := parseTrustedProxies(cfg.TrustedProxies) // checked by cfg.Validate
+	cache := newURLCache()
+
+	// The listener reads partition 0 only and relies on one gapless sequence
+	// per topic, so refuse to start if the topics are partitioned
+	topicCtx, cancelTopics := context.WithTimeout(context.Background(), 30*time.Second)
+	err = eventbus.EnsureSingleTopicPartition(topicCtx, cfg.Kafka.Brokers, updatedTopic, deletedTopic)
+	cancelTopics()
+	if err != nil {
+		log.Fatalf("invalidation topics are unusable: %v", err)
+	}
+	invalidations := newInvalidationListener(cfg.Kafka.Brokers, cache)
+	defer invalidations.Close()
+
 	r := mux.NewRouter()
+
 	r.HandleFunc("/{shortCode}", func(w http.ResponseWriter, r *http.Request) {
 		vars := mux.Vars(r)
 		shortCode := vars["shortCode"]
 
 		log.Printf("Received redirect request for short code: %s\n", shortCode)
 
-		// Call Shortener Service to get original URL
-		ctx, cancel := context.WithTimeout(r.Context(), time.Second)
-		defer cancel()
+		// Resolve through the cache, calling Shortener Service on a miss
+		res := cache.Get(shortCode, func(ctx context.Context) cachedURL {
+			res, err := shortenerClient.GetOriginalURL(ctx, &shortenerpb.GetOriginalURLRequest{
+				ShortCode: shortCode,
+			})
+			if err != nil {
+				return cachedURL{err: err}
+			}
+			if res.GetDisabled() {
+				return cachedURL{disabled: true}
+			}
 
-		res, err := shortenerClient.GetOriginalURL(ctx, &shortenerpb.GetOriginalURLRequest{
-			ShortCode: shortCode,
-		})
-		if err != nil {
-			log.Printf("Error getting original URL: %v", err)
+			var exp time.Time
+			if res.GetExpiresAt() != "" {
+				exp, _ = time.Parse(time.RFC3339, res.GetExpiresAt())
+			}
+			return cachedURL{longURL: res.GetLongUrl(), expiresAt: exp}
+		}, isNegativeResult)
+		if res.err != nil {
+			log.Printf("Error getting original URL: %v", res.err)
+			if status.Code(res.err) == codes.FailedPrecondition {
+				http.Error(w, "Short URL has expired", http.StatusNotFound)
+				return
+			}
 			http.Error(w, "Short URL not found or expired", http.StatusNotFound)
 			return
 		}
 
-		longURL := res.GetLongUrl()
-		expiresAt := res.GetExpiresAt()
+		if res.disabled {
+			http.Error(w, "Short URL has been disabled", http.StatusGone)
+			return
+		}
+
+		longURL := res.longURL
 
 		// Check for expiration (redundant with Shortener Service, but good for robustness)
-		if expiresAt != "" {
-			exp, parseErr := time.Parse(time.RFC3339, expiresAt)
-			if parseErr == nil && time.Now().After(exp) {
-				http.Error(w, "Short URL has expired", http.StatusNotFound)
-				return
-			}
+		if !res.expiresAt.IsZero() && time.Now().After(res.expiresAt) {
+			http.Error(w, "Short URL has expired", http.StatusNotFound)
+			return
 		}
 
-		// TODO: Publish click event to Kafka here
+		clicks.Publish(&eventspb.URLClickedV1{
+			ShortCode: shortCode,
+			ClickedAt: timestamppb.Now(),
+			UserAgent: r.UserAgent(),
+			Referer:   r.Referer(),
+			IpAddress: proxies.clientIP(r),
+			Country:   clientCountry(r),
+		})
+
 		log.Printf("Redirecting %s to %s\n", shortCode, longURL)
 		http.Redirect(w, r, longURL, http.StatusFound)
 
 	}).Methods("GET")
 
-	log.Printf("Redirect Service listening on :8081")
-	log.Fatal(http.ListenAndServe(":8081", r))
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	servers := []*http.Server{{Addr: cfg.ListenAddr, Handler: r}}
+	if cfg.DebugListenAddr != "" {
+		// Exposes the click event and url_cache_hits / url_cache_misses counters
+		debug := http.NewServeMux()
+		debug.Handle("GET /debug/vars", expvar.Handler())
+		servers = append(servers, &http.Server{Addr: cfg.DebugListenAddr, Handler: debug})
+		log.Printf("Debug endpoints listening on %s", cfg.DebugListenAddr)
+	}
+	for _, srv := range servers {
+		go func() {
+			if err := srv.ListenAndServe(); err != http.ErrServerClosed {
+				log.Fatalf("failed to serve on %s: %v", srv.Addr, err)
+			}
+		}()
+	}
+	log.Printf("Redirect Service listening on %s", cfg.ListenAddr)
+
+	<-ctx.Done()
+	log.Println("Shutting down redirect service...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	for _, srv := range servers {
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			log.Printf("Error shutting down %s: %v", srv.Addr, err)
+		}
+	}
+
+	// Publish the clicks of the requests that just finished
+	clicks.Close()
+	log.Println("Redirect service stopped")
 }
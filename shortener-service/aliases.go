@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// maxShortCodeLength is the width of urls.short_code
+const maxShortCodeLength = 20
+
+// aliasConfig controls which custom aliases users may claim
+type aliasConfig struct {
+	MinLength int `yaml:"min_length" env:"ALIAS_MIN_LENGTH" flag:"alias-min-length" default:"3" usage:"minimum length of custom aliases"`
+	MaxLength int `yaml:"max_length" env:"ALIAS_MAX_LENGTH" flag:"alias-max-length" default:"20" usage:"maximum length of custom aliases"`
+	// Paths that are served by something other than a redirect, matched
+	// case-insensitively. The defaults cover the gateway and redirect-service
+	// routes.
+	Reserved []string `yaml:"reserved" env:"RESERVED_ALIASES" flag:"reserved-aliases" default:"admin,api,auth,debug,health,login,logout,register,static" usage:"comma separated aliases nobody may claim"`
+	// A file of words, one per line, that custom aliases must not contain.
+	// Blank lines and lines starting with # are ignored.
+	BlocklistFile string `yaml:"blocklist_file" env:"ALIAS_BLOCKLIST_FILE" flag:"alias-blocklist-file" usage:"file of words custom aliases must not contain"`
+}
+
+func (c *aliasConfig) Validate() error {
+	if c.MinLength < 1 {
+		return fmt.Errorf("alias min length must be positive, got %d", c.MinLength)
+	}
+	if c.MaxLength < c.MinLength || c.MaxLength > maxShortCodeLength {
+		return fmt.Errorf("alias max length must be between %d and %d, got %d", c.MinLength, maxShortCodeLength, c.MaxLength)
+	}
+	return nil
+}
+
+// aliasValidator checks custom aliases before they are claimed
+type aliasValidator struct {
+	minLength int
+	maxLength int
+	reserved  map[string]bool
+	blocked   []string // Lower case
+}
+
+// newAliasValidator returns a validator for cfg, reading its blocklist file
+func newAliasValidator(cfg aliasConfig) (*aliasValidator, error) {
+	v := &aliasValidator{
+		minLength: cfg.MinLength,
+		maxLength: cfg.MaxLength,
+		reserved:  make(map[string]bool),
+	}
+	for _, word := range cfg.Reserved {
+		v.reserved[strings.ToLower(strings.TrimSpace(word))] = true
+	}
+
+	if cfg.BlocklistFile != "" {
+		blocked, err := readBlocklist(cfg.BlocklistFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read alias blocklist: %w", err)
+		}
+		v.blocked = blocked
+	}
+	return v, nil
+}
+
+func readBlocklist(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var blocked []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		word := strings.ToLower(strings.TrimSpace(scanner.Text()))
+		if word == "" || strings.HasPrefix(word, "#") {
+			continue
+		}
+		if word = squashAlias(word); word != "" {
+			blocked = append(blocked, word)
+		}
+	}
+	return blocked, scanner.Err()
+}
+
+// Validate returns the reason alias may not be claimed, or nil
+func (v *aliasValidator) Validate(alias string) error {
+	if len(alias) < v.minLength || len(alias) > v.maxLength {
+		return fmt.Errorf("must be between %d and %d characters long", v.minLength, v.maxLength)
+	}
+	for _, c := range alias {
+		if !isAliasChar(c) {
+			return fmt.Errorf("may only contain letters, digits, '-' and '_', got %q", c)
+		}
+	}
+
+	lower := strings.ToLower(alias)
+	if v.reserved[lower] {
+		return fmt.Errorf("%q is reserved", alias)
+	}
+
+	squashed := squashAlias(lower)
+	for _, word := range v.blocked {
+		if strings.Contains(squashed, word) {
+			return fmt.Errorf("contains a blocked word")
+		}
+	}
+	return nil
+}
+
+// squashAlias drops separators so they can't be used to split up a blocked
+// word
+func squashAlias(s string) string {
+	return strings.NewReplacer("-", "", "_", "").Replace(s)
+}
+
+func isAliasChar(c rune) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_'
+}
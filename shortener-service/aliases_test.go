@@ -0,0 +1,89 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// writeBlocklist writes a blocklist file for one test and returns its path
+func writeBlocklist(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "blocklist.txt")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestReadBlocklist(t *testing.T) {
+	path := writeBlocklist(t, "# Words nobody may put in an alias\n\nScam\n  free-money  \n# phish\nclick_here\n-_-\n")
+
+	got, err := readBlocklist(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"scam", "freemoney", "clickhere"}
+	if !slices.Equal(got, want) {
+		t.Errorf("readBlocklist = %q, want %q", got, want)
+	}
+
+	if _, err := readBlocklist(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Error("readBlocklist of a missing file succeeded")
+	}
+}
+
+func TestAliasValidate(t *testing.T) {
+	v, err := newAliasValidator(aliasConfig{
+		MinLength:     3,
+		MaxLength:     10,
+		Reserved:      []string{"admin", " API "},
+		BlocklistFile: writeBlocklist(t, "# comment\nscam\nfree-money\n"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		alias   string
+		wantErr string // Substring of the error, empty if the alias is allowed
+	}{
+		{alias: "abc"},
+		{alias: "abcdefghij"},
+		{alias: "My-Link_2"},
+		{alias: "ab", wantErr: "between 3 and 10 characters"},
+		{alias: "abcdefghijk", wantErr: "between 3 and 10 characters"},
+		// Length is counted in bytes, as short_code is stored
+		{alias: "éééééé", wantErr: "between 3 and 10 characters"},
+		{alias: "café", wantErr: "may only contain"},
+		{alias: "日本語", wantErr: "may only contain"},
+		{alias: "a.b.c", wantErr: "may only contain"},
+		{alias: "a b c", wantErr: "may only contain"},
+		{alias: "a/b/c", wantErr: "may only contain"},
+		{alias: "admin", wantErr: "is reserved"},
+		{alias: "ADMIN", wantErr: "is reserved"},
+		{alias: "Api", wantErr: "is reserved"},
+		{alias: "admins"},
+		{alias: "scam", wantErr: "blocked word"},
+		{alias: "BigScam", wantErr: "blocked word"},
+		{alias: "s-c_a-m", wantErr: "blocked word"},
+		{alias: "free_money", wantErr: "blocked word"},
+		{alias: "FreeMoney1", wantErr: "blocked word"},
+		{alias: "freemoon"},
+	}
+
+	for _, tt := range tests {
+		err := v.Validate(tt.alias)
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("Validate(%q) = %v, want nil", tt.alias, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("Validate(%q) = %v, want error containing %q", tt.alias, err, tt.wantErr)
+		}
+	}
+}
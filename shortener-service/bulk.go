@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"io"
+	"log"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Farhang-Osman/url-shortener-project/common/outbox"
+	"github.com/Farhang-Osman/url-shortener-project/common/repository"
+	shortenerpb "github.com/Farhang-Osman/url-shortener-project/pkg/proto/shortenerpb"
+)
+
+const (
+	maxBulkRows   = 10000
+	bulkBatchSize = 500
+)
+
+// bulkRow is a valid row waiting to be stored
+type bulkRow struct {
+	url    *repository.URL
+	result *shortenerpb.BulkShortenResult
+	alias  bool // The short code is a custom alias and must not be replaced
+}
+
+func setRowError(res *shortenerpb.BulkShortenResult, err error) {
+	st := status.Convert(err)
+	res.ErrorCode = st.Code().String()
+	res.Error = st.Message()
+}
+
+// BulkShortenURLs shortens every URL sent on the stream and reports the
+// outcome per row. Rows are validated as they arrive and stored in batches,
+// each batch in one transaction; a row that fails never affects the others.
+func (s *server) BulkShortenURLs(stream shortenerpb.ShortenerService_BulkShortenURLsServer) error {
+	ctx := stream.Context()
+	res := &shortenerpb.BulkShortenURLsResponse{}
+	var pending []bulkRow
+
+	for row := 0; ; row++ {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if row == maxBulkRows {
+			return status.Errorf(codes.InvalidArgument, "at most %d URLs can be shortened at once", maxBulkRows)
+		}
+
+		result := &shortenerpb.BulkShortenResult{Row: int32(row)}
+		res.Results = append(res.Results, result)
+
+		u, err := s.newURL(req)
+		if err != nil {
+			setRowError(result, err)
+			continue
+		}
+		u.ShortCode = req.GetCustomAlias()
+		if u.ShortCode == "" {
+			if u.ShortCode, err = s.codes.Next(ctx); err != nil {
+				setRowError(result, status.Errorf(codes.Internal, "failed to generate short code: %v", err))
+				continue
+			}
+		}
+		pending = append(pending, bulkRow{url: u, result: result, alias: req.GetCustomAlias() != ""})
+
+		if len(pending) == bulkBatchSize {
+			s.storeBulkRows(ctx, pending)
+			pending = pending[:0]
+		}
+	}
+	s.storeBulkRows(ctx, pending)
+
+	for _, result := range res.Results {
+		if result.ErrorCode == "" {
+			res.Created++
+		} else {
+			res.Failed++
+		}
+	}
+	log.Printf("Bulk shortened %d URLs, %d failed", res.Created, res.Failed)
+
+	return stream.SendAndClose(res)
+}
+
+// storeBulkRows stores one batch and fills in the rows' results. Custom
+// aliases that are taken are reported as such; generated codes that collide
+// are replaced and retried with the next round.
+func (s *server) storeBulkRows(ctx context.Context, rows []bulkRow) {
+	for attempt := 1; len(rows) > 0; attempt++ {
+		urls := make([]*repository.URL, len(rows))
+		for i, row := range rows {
+			urls[i] = row.url
+		}
+
+		created, err := s.urls.CreateBatch(ctx, urls, func(u *repository.URL) repository.Event {
+			return outbox.URLCreated(u.ShortCode, u.LongURL, u.UserID, u.CreatedAt)
+		})
+		if err != nil {
+			log.Printf("failed to store bulk URLs: %v", err)
+			for _, row := range rows {
+				setRowError(row.result, status.Errorf(codes.Internal, "failed to store URL: %v", err))
+			}
+			return
+		}
+
+		var retry []bulkRow
+		for i, row := range rows {
+			switch {
+			case created[i]:
+				row.result.ShortCode = row.url.ShortCode
+				row.result.ShortUrl = s.shortURL(row.url.ShortCode)
+			case row.alias:
+				setRowError(row.result, status.Errorf(codes.AlreadyExists, "custom alias already exists"))
+			case attempt == maxCodeAttempts:
+				setRowError(row.result, status.Errorf(codes.Internal, "failed to generate a unique short code"))
+			default:
+				code, err := s.codes.Next(ctx)
+				if err != nil {
+					setRowError(row.result, status.Errorf(codes.Internal, "failed to generate short code: %v", err))
+					continue
+				}
+				row.url.ShortCode = code
+				retry = append(retry, row)
+			}
+		}
+		rows = retry
+	}
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/Farhang-Osman/url-shortener-project/common/eventbus"
+	"github.com/Farhang-Osman/url-shortener-project/common/events"
+	"github.com/Farhang-Osman/url-shortener-project/common/repository"
+)
+
+const (
+	clickTopic         = "url-click-events"
+	clickBufferSize    = 4096
+	clickFlushInterval = 2 * time.Second
+	// Event IDs are kept as long as the click topic and its dead-letter
+	// queue keep messages that could be delivered again
+	clickDedupRetention = 7 * 24 * time.Hour
+	clickPruneInterval  = time.Hour
+)
+
+// fetchedClick is one message from the click topic. click is nil if the
+// message could not be decoded; it is still committed with the others.
+type fetchedClick struct {
+	msg   eventbus.Message
+	click *repository.Click
+}
+
+// clickRecorder keeps click_count and last_accessed up to date from the click
+// events redirect-service publishes for every redirect, including those
+// served from its cache. Clicks are flushed to the database in batches and
+// offsets committed only after a flush succeeds. Event IDs are recorded with
+// the counters, so events delivered again after a crash or replayed from the
+// dead-letter queue are not counted twice.
+type clickRecorder struct {
+	urls   repository.URLRepository
+	sub    eventbus.Subscriber
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func newClickRecorder(urls repository.URLRepository, sub eventbus.Subscriber) *clickRecorder {
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &clickRecorder{urls: urls, sub: sub, cancel: cancel}
+
+	fetched := make(chan fetchedClick, clickBufferSize)
+	r.wg.Add(2)
+	go r.fetch(ctx, fetched)
+	go r.run(fetched)
+	return r
+}
+
+// Close stops fetching and returns after the clicks already fetched are
+// flushed. The subscriber is left to the caller to close.
+func (r *clickRecorder) Close() {
+	r.cancel()
+	r.wg.Wait()
+}
+
+func (r *clickRecorder) fetch(ctx context.Context, fetched chan<- fetchedClick) {
+	defer r.wg.Done()
+	defer close(fetched)
+
+	for {
+		msg, err := r.sub.Fetch(ctx)
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, eventbus.ErrClosed) {
+				return
+			}
+			log.Printf("Error fetching click event: %v", err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(time.Second):
+			}
+			continue
+		}
+
+		c := fetchedClick{msg: msg}
+		env, err := events.Decode(msg.Value, msg.Headers[events.HeaderContentType], events.TypeURLClicked)
+		if err == nil && env.GetEventType() == events.TypeURLClicked {
+			click := env.GetUrlClicked()
+			c.click = &repository.Click{
+				EventID:   env.GetEventId(),
+				ShortCode: click.GetShortCode(),
+				ClickedAt: click.GetClickedAt().AsTime(),
+			}
+		} else {
+			// analytics-service dead-letters these; here they are only skipped
+			log.Printf("Warning: skipping undecodable click event at offset %d: %v", msg.Offset, err)
+		}
+
+		select {
+		case fetched <- c:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (r *clickRecorder) run(fetched <-chan fetchedClick) {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(clickFlushInterval)
+	defer ticker.Stop()
+
+	var pending []repository.Click
+	// The last message per partition; committing it commits the ones before
+	offsets := make(map[int]eventbus.Message)
+	lastPrune := time.Time{}
+	for {
+		select {
+		case c, ok := <-fetched:
+			if !ok {
+				r.flush(pending, offsets)
+				return
+			}
+			if c.click != nil && c.click.ShortCode != "" {
+				pending = append(pending, *c.click)
+			}
+			offsets[c.msg.Partition] = c.msg
+		case <-ticker.C:
+			// After a failed flush the clicks are kept and retried on the
+			// next tick
+			if r.flush(pending, offsets) {
+				pending = nil
+				offsets = make(map[int]eventbus.Message)
+			}
+			if time.Since(lastPrune) > clickPruneInterval {
+				r.prune()
+				lastPrune = time.Now()
+			}
+		}
+	}
+}
+
+// flush adds the pending clicks to the database and commits the messages
+// they came from. It reports whether the clicks were stored.
+func (r *clickRecorder) flush(pending []repository.Click, offsets map[int]eventbus.Message) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if len(pending) > 0 {
+		if err := r.urls.AddClicks(ctx, pending); err != nil {
+			log.Printf("Warning: failed to flush %d clicks: %v", len(pending), err)
+			return false
+		}
+	}
+
+	if len(offsets) > 0 {
+		msgs := make([]eventbus.Message, 0, len(offsets))
+		for _, msg := range offsets {
+			msgs = append(msgs, msg)
+		}
+		if err := r.sub.Commit(ctx, msgs...); err != nil {
+			log.Printf("Warning: failed to commit click events: %v", err)
+		}
+	}
+	return true
+}
+
+// prune forgets the event IDs of clicks counted longer ago than
+// clickDedupRetention
+func (r *clickRecorder) prune() {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := r.urls.PruneProcessedClicks(ctx, time.Now().Add(-clickDedupRetention)); err != nil {
+		log.Printf("Warning: failed to prune processed click events: %v", err)
+	}
+}
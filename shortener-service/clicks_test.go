@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/Farhang-Osman/url-shortener-project/common/eventbus"
+	"github.com/Farhang-Osman/url-shortener-project/common/events"
+	"github.com/Farhang-Osman/url-shortener-project/common/outbox"
+	"github.com/Farhang-Osman/url-shortener-project/common/repository"
+	eventspb "github.com/Farhang-Osman/url-shortener-project/pkg/proto/eventspb"
+)
+
+// TestClickRecorderDeduplicates delivers the same click event twice, as a
+// redelivery after a failed commit or a dead-letter replay would, and checks
+// that it is counted once
+func TestClickRecorderDeduplicates(t *testing.T) {
+	// Clicks are flushed every clickFlushInterval, twice in this test
+	ctx, cancel := context.WithTimeout(context.Background(), 5*clickFlushInterval)
+	defer cancel()
+
+	urls := repository.NewMemoryURLRepository()
+	u := &repository.URL{ShortCode: "launch", LongURL: "https://example.com/", UserID: testUserID, CreatedAt: time.Now()}
+	if err := urls.Create(ctx, u, outbox.URLCreated(u.ShortCode, u.LongURL, u.UserID, u.CreatedAt)); err != nil {
+		t.Fatal(err)
+	}
+
+	bus := eventbus.NewMemory()
+	publisher := bus.Publisher()
+	clickedAt := time.Now().Add(-time.Minute).UTC()
+	click := func() eventbus.Message {
+		payload, err := events.Marshal(events.NewURLClicked(&eventspb.URLClickedV1{
+			ShortCode: "launch",
+			ClickedAt: timestamppb.New(clickedAt),
+		}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return eventbus.Message{
+			Topic:   clickTopic,
+			Key:     []byte("launch"),
+			Value:   payload,
+			Headers: map[string]string{events.HeaderContentType: events.ContentTypeProtobuf},
+		}
+	}
+
+	sub := bus.Subscriber(clickTopic, "shortener-click-group")
+	defer sub.Close()
+	clicks := newClickRecorder(urls, sub)
+	defer clicks.Close()
+
+	// waitForClicks waits until click_count reaches want and checks that it
+	// doesn't go past it
+	waitForClicks := func(want int64) {
+		t.Helper()
+		for {
+			got, err := urls.Get(ctx, "launch")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.ClickCount >= want {
+				if got.ClickCount != want {
+					t.Fatalf("click_count = %d, want %d", got.ClickCount, want)
+				}
+				return
+			}
+			select {
+			case <-ctx.Done():
+				t.Fatalf("timed out with click_count = %d, want %d", got.ClickCount, want)
+			case <-time.After(20 * time.Millisecond):
+			}
+		}
+	}
+
+	// A duplicate within one batch
+	repeated := click()
+	if err := publisher.Publish(ctx, repeated, repeated, click()); err != nil {
+		t.Fatal(err)
+	}
+	waitForClicks(2)
+
+	// A duplicate in a later batch, followed by a new click that is flushed
+	// with or after it
+	if err := publisher.Publish(ctx, repeated, click()); err != nil {
+		t.Fatal(err)
+	}
+	waitForClicks(3)
+
+	got, err := urls.Get(ctx, "launch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.LastAccessed == nil || !got.LastAccessed.Equal(clickedAt) {
+		t.Errorf("last_accessed = %v, want %v", got.LastAccessed, clickedAt)
+	}
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/bits"
+	"strings"
+	"sync"
+
+	"github.com/Farhang-Osman/url-shortener-project/common/repository"
+)
+
+// Short code generators
+const (
+	codeGeneratorSequence = "sequence"
+	codeGeneratorRandom   = "random"
+)
+
+const (
+	minCodeLength = 4
+	// 62^10 is the largest power of 62 that fits in an int64
+	maxCodeLength = 10
+	// How many generated codes are tried for one link before giving up
+	maxCodeAttempts = 5
+)
+
+// codeConfig selects how short codes are generated when no custom alias is
+// given
+type codeConfig struct {
+	Generator string `yaml:"generator" env:"CODE_GENERATOR" flag:"code-generator" default:"sequence" usage:"short code generator: sequence or random"`
+	Length    int    `yaml:"length" env:"CODE_LENGTH" flag:"code-length" default:"7" usage:"length of generated short codes"`
+}
+
+func (c *codeConfig) Validate() error {
+	if c.Generator != codeGeneratorSequence && c.Generator != codeGeneratorRandom {
+		return fmt.Errorf("code generator must be %s or %s, got %q", codeGeneratorSequence, codeGeneratorRandom, c.Generator)
+	}
+	if c.Length < minCodeLength || c.Length > maxCodeLength {
+		return fmt.Errorf("code length must be between %d and %d, got %d", minCodeLength, maxCodeLength, c.Length)
+	}
+	return nil
+}
+
+// CodeGenerator produces short codes for links without a custom alias.
+// Generated codes may still collide with custom aliases or codes from
+// another generator, so callers must handle a conflict on insert.
+type CodeGenerator interface {
+	Next(ctx context.Context) (string, error)
+}
+
+// newCodeGenerator returns the generator selected by cfg
+func newCodeGenerator(cfg codeConfig, ranges repository.CodeRangeRepository) CodeGenerator {
+	if cfg.Generator == codeGeneratorRandom {
+		return randomGenerator{length: cfg.Length}
+	}
+	return newSequenceGenerator(ranges, cfg.Length)
+}
+
+// randomGenerator draws codes from crypto/rand using the URL-safe base64
+// alphabet. Codes are unrelated to each other, so collisions become likelier
+// as the keyspace fills up.
+type randomGenerator struct {
+	length int
+}
+
+func (g randomGenerator) Next(ctx context.Context) (string, error) {
+	// Every byte carries 8 bits and every character 6
+	bytes := make([]byte, (g.length*6+7)/8)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+
+	// Encode to base64 and clean up
+	encoded := base64.URLEncoding.EncodeToString(bytes)
+	// Remove padding and make it URL-safe
+	encoded = strings.TrimRight(encoded, "=")
+
+	return encoded[:g.length], nil
+}
+
+// base62Alphabet is shuffled so codes don't read as counting numbers
+const base62Alphabet = "hWviBKrTq2U3DpVoy68HzJnOLeNY9cSCsk5dIlRxFu17MEg0PZwamGjtfQ4bAX"
+
+// Constants of the permutation that scatters consecutive IDs over the
+// keyspace. The multiplier is odd and not divisible by 31, so it is coprime
+// with every power of 62.
+const (
+	codeMultiplier = 25214903917
+	codeOffset     = 11
+)
+
+var errCodeSpaceExhausted = errors.New("all short codes of the configured length are taken; increase the code length")
+
+// sequenceGenerator turns IDs reserved from the database in ranges into
+// codes. Every ID is handed out once across all instances, and distinct IDs
+// give distinct codes, so codes never collide with each other and only one
+// query is needed per repository.CodeRangeSize codes. IDs are permuted
+// before encoding so consecutive codes look unrelated.
+type sequenceGenerator struct {
+	ranges repository.CodeRangeRepository
+	length int
+	space  uint64 // 62^length
+
+	mu   sync.Mutex
+	next int64
+	end  int64
+}
+
+func newSequenceGenerator(ranges repository.CodeRangeRepository, length int) *sequenceGenerator {
+	space := uint64(1)
+	for range length {
+		space *= 62
+	}
+	return &sequenceGenerator{ranges: ranges, length: length, space: space}
+}
+
+func (g *sequenceGenerator) Next(ctx context.Context) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.next == g.end {
+		start, err := g.ranges.NextCodeRange(ctx)
+		if err != nil {
+			return "", fmt.Errorf("failed to reserve short code range: %w", err)
+		}
+		g.next, g.end = start, start+repository.CodeRangeSize
+	}
+	id := uint64(g.next)
+	if id >= g.space {
+		return "", errCodeSpaceExhausted
+	}
+	g.next++
+
+	return encodeBase62(g.permute(id), g.length), nil
+}
+
+// permute maps id to (id*codeMultiplier + codeOffset) mod 62^length, which is
+// a bijection on [0, 62^length)
+func (g *sequenceGenerator) permute(id uint64) uint64 {
+	hi, lo := bits.Mul64(id, codeMultiplier)
+	lo, carry := bits.Add64(lo, codeOffset, 0)
+	return bits.Rem64(hi+carry, lo, g.space)
+}
+
+// encodeBase62 writes n as exactly length base62 digits
+func encodeBase62(n uint64, length int) string {
+	code := make([]byte, length)
+	for i := length - 1; i >= 0; i-- {
+		code[i] = base62Alphabet[n%62]
+		n /= 62
+	}
+	return string(code)
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Farhang-Osman/url-shortener-project/common/repository"
+)
+
+// fixedRanges hands out the given range starts in order
+type fixedRanges struct {
+	starts []int64
+}
+
+func (r *fixedRanges) NextCodeRange(ctx context.Context) (int64, error) {
+	if len(r.starts) == 0 {
+		return 0, errors.New("no more ranges")
+	}
+	start := r.starts[0]
+	r.starts = r.starts[1:]
+	return start, nil
+}
+
+func TestSequenceGenerator(t *testing.T) {
+	const length = 4
+	const space = 62 * 62 * 62 * 62
+	const lastIDs = 10
+
+	// Two adjacent ranges, then one that runs past the end of the keyspace
+	ranges := &fixedRanges{starts: []int64{0, repository.CodeRangeSize, space - lastIDs}}
+	gen := newSequenceGenerator(ranges, length)
+	ctx := context.Background()
+
+	seen := make(map[string]bool)
+	next := func() string {
+		t.Helper()
+		code, err := gen.Next(ctx)
+		if err != nil {
+			t.Fatalf("code %d: %v", len(seen), err)
+		}
+		if len(code) != length {
+			t.Fatalf("code %q is %d characters long, want %d", code, len(code), length)
+		}
+		for _, c := range code {
+			if !strings.ContainsRune(base62Alphabet, c) {
+				t.Fatalf("code %q contains %q, which is not in the base62 alphabet", code, c)
+			}
+		}
+		if seen[code] {
+			t.Fatalf("code %q handed out twice", code)
+		}
+		seen[code] = true
+		return code
+	}
+
+	for range 2*repository.CodeRangeSize + lastIDs {
+		next()
+	}
+	if len(ranges.starts) != 0 {
+		t.Errorf("%d ranges left unused", len(ranges.starts))
+	}
+
+	for range 2 {
+		if code, err := gen.Next(ctx); !errors.Is(err, errCodeSpaceExhausted) {
+			t.Fatalf("Next past the keyspace = %q, %v; want errCodeSpaceExhausted", code, err)
+		}
+	}
+}
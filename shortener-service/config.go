@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/Farhang-Osman/url-shortener-project/common/config"
+)
+
+// Config is loaded from the environment, flags and an optional YAML file;
+// see package config
+type Config struct {
+	ListenAddr string          `yaml:"listen_addr" env:"LISTEN_ADDR" flag:"listen" default:":50052" usage:"gRPC listen address"`
+	Database   config.Database `yaml:"database"`
+	Kafka      config.Kafka    `yaml:"kafka"`
+	Codes      codeConfig      `yaml:"codes"`
+	Aliases    aliasConfig     `yaml:"aliases"`
+	// Destinations on the host of PublicBaseURL are always rejected
+	Destinations destinationConfig `yaml:"destinations"`
+	// Where redirect-service is reachable from the outside; short URLs
+	// returned to clients are built from it
+	PublicBaseURL string `yaml:"public_base_url" env:"PUBLIC_BASE_URL" flag:"public-base-url" default:"http://localhost:8081" usage:"public base URL of the redirect service"`
+}
+
+func (c *Config) Validate() error {
+	u, err := url.Parse(c.PublicBaseURL)
+	if err != nil {
+		return fmt.Errorf("invalid public base URL: %v", err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("public base URL must be an absolute http or https URL")
+	}
+	if u.RawQuery != "" || u.Fragment != "" {
+		return fmt.Errorf("public base URL must not have a query or fragment")
+	}
+	return nil
+}
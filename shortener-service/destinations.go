@@ -0,0 +1,149 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+// destinationConfig controls which destinations links may point to
+type destinationConfig struct {
+	AllowPrivateHosts bool `yaml:"allow_private_hosts" env:"ALLOW_PRIVATE_DESTINATIONS" flag:"allow-private-destinations" usage:"accept destinations on loopback, private and link-local hosts"`
+}
+
+var defaultPorts = map[string]string{"http": "80", "https": "443"}
+
+// hostProfile maps host names the way browsers do, which unlike
+// idna.Lookup allows underscores
+var hostProfile = idna.New(idna.MapForLookup(), idna.BidiRule(), idna.StrictDomainName(false))
+
+// destinationValidator checks and normalizes the URLs links redirect to
+type destinationValidator struct {
+	allowPrivate bool
+	ownHost      string // Host of the public base URL, normalized
+}
+
+// newDestinationValidator returns a validator that also rejects links back
+// to baseURL, which would redirect in a loop
+func newDestinationValidator(cfg destinationConfig, baseURL string) (*destinationValidator, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, err
+	}
+	ownHost, err := normalizeHost(u.Hostname())
+	if err != nil {
+		return nil, fmt.Errorf("invalid public base URL host: %w", err)
+	}
+	return &destinationValidator{allowPrivate: cfg.AllowPrivateHosts, ownHost: ownHost}, nil
+}
+
+// Normalize validates longURL and returns it with the scheme and host in
+// lower case, an internationalized host converted to punycode and a default
+// port removed
+func (v *destinationValidator) Normalize(longURL string) (string, error) {
+	longURL = strings.TrimSpace(longURL)
+	if longURL == "" {
+		return "", errors.New("URL must not be empty")
+	}
+
+	u, err := url.Parse(longURL)
+	if err != nil {
+		return "", err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", errors.New("URL scheme must be http or https")
+	}
+	if u.Opaque != "" || u.Host == "" {
+		return "", errors.New("URL must be absolute and have a host")
+	}
+	// https://trusted.example@evil.example is a common way to disguise
+	// where a link leads
+	if u.User != nil {
+		return "", errors.New("URL must not contain credentials")
+	}
+
+	host, err := normalizeHost(u.Hostname())
+	if err != nil {
+		return "", err
+	}
+	if host == v.ownHost {
+		return "", errors.New("URL must not point back at this short link service")
+	}
+	if !v.allowPrivate && isPrivateHost(host) {
+		return "", errors.New("URL must not point at a loopback or private network host")
+	}
+
+	port := u.Port()
+	if port != "" {
+		if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+			return "", fmt.Errorf("invalid port %q", port)
+		}
+	}
+	if port == defaultPorts[u.Scheme] {
+		port = ""
+	}
+
+	u.Host = host
+	if strings.Contains(host, ":") {
+		u.Host = "[" + host + "]"
+	}
+	if port != "" {
+		u.Host = net.JoinHostPort(host, port)
+	}
+	return u.String(), nil
+}
+
+// normalizeHost lower-cases a host name, converts it to its ASCII form and
+// drops a trailing dot. IP addresses are returned in canonical form.
+func normalizeHost(host string) (string, error) {
+	if host == "" {
+		return "", errors.New("URL must have a host")
+	}
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return addr.Unmap().WithZone("").String(), nil
+	}
+
+	ascii, err := hostProfile.ToASCII(strings.TrimSuffix(host, "."))
+	if err != nil {
+		return "", fmt.Errorf("invalid host %q: %v", host, err)
+	}
+	if ascii == "" || strings.HasPrefix(ascii, ".") || strings.Contains(ascii, "..") {
+		return "", fmt.Errorf("invalid host %q: empty label", host)
+	}
+	if endsInNumber(ascii) {
+		return "", fmt.Errorf("invalid host %q: IPv4 addresses must be written in dotted decimal", host)
+	}
+	return ascii, nil
+}
+
+// endsInNumber reports whether browsers parse host as an IPv4 address, as
+// they do with 2130706433 or 0x7f.1
+func endsInNumber(host string) bool {
+	last := host[strings.LastIndex(host, ".")+1:]
+	if last == "" {
+		return false
+	}
+	if strings.Trim(last, "0123456789") == "" {
+		return true
+	}
+	hex, ok := strings.CutPrefix(last, "0x")
+	return ok && strings.Trim(hex, "0123456789abcdef") == ""
+}
+
+// isPrivateHost reports whether a normalized host is a loopback, private,
+// link-local or unspecified address, or a name reserved for the local
+// machine. Other names are not resolved: what they point to can change after
+// the link is created.
+func isPrivateHost(host string) bool {
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return addr.IsLoopback() || addr.IsPrivate() || addr.IsLinkLocalUnicast() ||
+			addr.IsLinkLocalMulticast() || addr.IsUnspecified()
+	}
+	return host == "localhost" || strings.HasSuffix(host, ".localhost")
+}
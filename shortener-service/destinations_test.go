@@ -0,0 +1,92 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDestinationNormalize(t *testing.T) {
+	tests := []struct {
+		name         string
+		longURL      string
+		allowPrivate bool
+		want         string
+		wantErr      string // Substring of the error, empty if none is expected
+	}{
+		{name: "javascript", longURL: "javascript:alert(1)", wantErr: "scheme must be http or https"},
+		{name: "relative", longURL: "/launch", wantErr: "scheme must be http or https"},
+		{name: "empty", longURL: "", wantErr: "must not be empty"},
+		{name: "blank", longURL: "  ", wantErr: "must not be empty"},
+		{name: "userinfo", longURL: "https://trusted.example@evil.example/", wantErr: "must not contain credentials"},
+		{name: "short IPv4", longURL: "http://127.1/", wantErr: "dotted decimal"},
+		{name: "hex IPv4", longURL: "http://0x7f.1/", wantErr: "dotted decimal"},
+		{name: "IPv4-mapped IPv6", longURL: "http://[::ffff:127.0.0.1]/", wantErr: "loopback or private"},
+		{name: "own host", longURL: "https://sho.rt/abc", wantErr: "back at this short link service"},
+		{name: "own host trailing dot", longURL: "https://SHO.RT./abc", wantErr: "back at this short link service"},
+		{name: "IDN", longURL: "https://Bücher.example/katalog", want: "https://xn--bcher-kva.example/katalog"},
+		{name: "default http port", longURL: "HTTP://Example.COM:80/Path?q=1", want: "http://example.com/Path?q=1"},
+		{name: "default https port", longURL: "https://example.com:443/", want: "https://example.com/"},
+		{name: "non-default port", longURL: "https://example.com:8443/", want: "https://example.com:8443/"},
+		{name: "http port on https", longURL: "https://example.com:80/", want: "https://example.com:80/"},
+		{name: "IPv6 with port", longURL: "http://[2001:DB8::1]:8080/x", want: "http://[2001:db8::1]:8080/x"},
+		{name: "IPv6 default port", longURL: "http://[2001:db8::1]:80/", want: "http://[2001:db8::1]/"},
+		{name: "invalid port", longURL: "http://example.com:99999/", wantErr: "invalid port"},
+		{name: "localhost", longURL: "http://localhost:3000/", wantErr: "loopback or private"},
+		{name: "private IPv4", longURL: "http://10.0.0.1/", wantErr: "loopback or private"},
+		{name: "localhost allowed", longURL: "http://localhost:3000/", allowPrivate: true, want: "http://localhost:3000/"},
+		{name: "private IPv4 allowed", longURL: "http://10.0.0.1/", allowPrivate: true, want: "http://10.0.0.1/"},
+		{name: "own host with private allowed", longURL: "https://sho.rt/", allowPrivate: true, wantErr: "back at this short link service"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := newDestinationValidator(destinationConfig{AllowPrivateHosts: tt.allowPrivate}, "https://sho.rt")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := v.Normalize(tt.longURL)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Normalize(%q) = %q, %v; want error containing %q", tt.longURL, got, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Fatalf("Normalize(%q) = %q, %v; want %q", tt.longURL, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeHost(t *testing.T) {
+	tests := []struct {
+		host    string
+		want    string
+		wantErr bool
+	}{
+		{host: "Example.COM.", want: "example.com"},
+		{host: "BÜCHER.example", want: "xn--bcher-kva.example"},
+		{host: "my_host.example", want: "my_host.example"},
+		{host: "::ffff:127.0.0.1", want: "127.0.0.1"},
+		{host: "2001:DB8::1", want: "2001:db8::1"},
+		{host: "127.1", wantErr: true},
+		{host: "0x7f.1", wantErr: true},
+		{host: "2130706433", wantErr: true},
+		{host: "a..example", wantErr: true},
+		{host: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := normalizeHost(tt.host)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("normalizeHost(%q) = %q, want an error", tt.host, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("normalizeHost(%q) = %q, %v; want %q", tt.host, got, err, tt.want)
+		}
+	}
+}
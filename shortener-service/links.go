@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"log"
+
+	"github.com/Farhang-Osman/url-shortener-project/common/outbox"
+	"github.com/Farhang-Osman/url-shortener-project/common/repository"
+	shortenerpb "github.com/Farhang-Osman/url-shortener-project/pkg/proto/shortenerpb"
+)
+
+// DeleteURL removes an owned URL and its revisions for good. The url-deleted
+// event evicts it from redirect caches and drops its analytics.
+func (s *server) DeleteURL(ctx context.Context, req *shortenerpb.DeleteURLRequest) (*shortenerpb.DeleteURLResponse, error) {
+	log.Printf("Received DeleteURL request: %v\n", req.GetShortCode())
+
+	userID := req.GetUserId()
+	err := s.urls.Delete(ctx, req.GetShortCode(),
+		func(u *repository.URL) error {
+			return checkOwner(u, userID)
+		},
+		func(u *repository.URL) repository.Event {
+			return outbox.URLDeleted(u.ShortCode, userID)
+		})
+	if err != nil {
+		return nil, urlError(err)
+	}
+
+	log.Printf("URL deleted: %s", req.GetShortCode())
+
+	return &shortenerpb.DeleteURLResponse{
+		ShortCode: req.GetShortCode(),
+		Message:   "URL deleted successfully",
+	}, nil
+}
+
+// SetURLActive disables or re-enables an owned URL. Disabled URLs keep their
+// short code and history, but redirects answer 410 Gone.
+func (s *server) SetURLActive(ctx context.Context, req *shortenerpb.SetURLActiveRequest) (*shortenerpb.SetURLActiveResponse, error) {
+	log.Printf("Received SetURLActive request: %v -> %v\n", req.GetShortCode(), req.GetActive())
+
+	userID := req.GetUserId()
+	u, err := s.urls.SetActive(ctx, req.GetShortCode(), req.GetActive(),
+		func(u *repository.URL) error {
+			return checkOwner(u, userID)
+		},
+		func(u *repository.URL) repository.Event {
+			return outbox.URLUpdated(u, userID)
+		})
+	if err != nil {
+		return nil, urlError(err)
+	}
+
+	message := "URL disabled successfully"
+	if u.Active {
+		message = "URL enabled successfully"
+	}
+	log.Printf("URL %s active: %v", u.ShortCode, u.Active)
+
+	return &shortenerpb.SetURLActiveResponse{
+		ShortCode: u.ShortCode,
+		Active:    u.Active,
+		Message:   message,
+	}, nil
+}
+
+// GetURLInfo returns everything stored about an owned URL. Unlike
+// GetOriginalURL it doesn't hide disabled or expired links.
+func (s *server) GetURLInfo(ctx context.Context, req *shortenerpb.GetURLInfoRequest) (*shortenerpb.URLInfo, error) {
+	log.Printf("Received GetURLInfo request: %v\n", req.GetShortCode())
+
+	u, err := s.ownedURL(ctx, req.GetShortCode(), req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+	return s.urlInfo(u), nil
+}
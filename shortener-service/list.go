@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"log"
+	"net/url"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Farhang-Osman/url-shortener-project/common/repository"
+	shortenerpb "github.com/Farhang-Osman/url-shortener-project/pkg/proto/shortenerpb"
+)
+
+const (
+	defaultListPageSize = 20
+	maxListPageSize     = 100
+)
+
+// pageToken is the opaque ListURLs page token: the position of the last URL
+// on the previous page along with the ordering it was taken from
+type pageToken struct {
+	SortBy    string    `json:"s"`
+	Ascending bool      `json:"a"`
+	Time      time.Time `json:"t,omitzero"`
+	Count     int64     `json:"c,omitempty"`
+	ID        string    `json:"i"`
+}
+
+func encodePageToken(t pageToken) string {
+	data, _ := json.Marshal(t)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodePageToken(s string) (pageToken, error) {
+	var t pageToken
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return t, err
+	}
+	err = json.Unmarshal(data, &t)
+	return t, err
+}
+
+// shortURL returns the public address of a short code
+func (s *server) shortURL(shortCode string) string {
+	return s.baseURL + "/" + url.PathEscape(shortCode)
+}
+
+// urlInfo converts a stored URL for the API
+func (s *server) urlInfo(u *repository.URL) *shortenerpb.URLInfo {
+	return &shortenerpb.URLInfo{
+		ShortCode:    u.ShortCode,
+		LongUrl:      u.LongURL,
+		Active:       u.Active,
+		ExpiresAt:    formatOptionalTime(u.ExpiresAt),
+		CreatedAt:    u.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:    u.UpdatedAt.Format(time.RFC3339),
+		ClickCount:   u.ClickCount,
+		LastAccessed: formatOptionalTime(u.LastAccessed),
+		UserId:       u.UserID,
+		ShortUrl:     s.shortURL(u.ShortCode),
+		Tags:         u.Tags,
+	}
+}
+
+// listQuery validates a ListURLs request and translates it for the repository
+func listQuery(req *shortenerpb.ListURLsRequest) (repository.URLListQuery, error) {
+	q := repository.URLListQuery{
+		UserID:    req.GetUserId(),
+		Ascending: req.GetOrder() == shortenerpb.SortOrder_SORT_ORDER_ASC,
+		Search:    req.GetQuery(),
+		Limit:     defaultListPageSize,
+	}
+	if q.UserID == "" {
+		return q, status.Errorf(codes.Unauthenticated, "user_id is required")
+	}
+
+	switch size := req.GetPageSize(); {
+	case size < 0:
+		return q, status.Errorf(codes.InvalidArgument, "page_size must not be negative")
+	case size > maxListPageSize:
+		q.Limit = maxListPageSize
+	case size > 0:
+		q.Limit = int(size)
+	}
+
+	switch req.GetSortBy() {
+	case shortenerpb.URLSortField_URL_SORT_FIELD_UNSPECIFIED, shortenerpb.URLSortField_URL_SORT_FIELD_CREATED_AT:
+		q.SortBy = repository.URLSortCreatedAt
+	case shortenerpb.URLSortField_URL_SORT_FIELD_CLICK_COUNT:
+		q.SortBy = repository.URLSortClickCount
+	case shortenerpb.URLSortField_URL_SORT_FIELD_LAST_ACCESSED:
+		q.SortBy = repository.URLSortLastAccessed
+	default:
+		return q, status.Errorf(codes.InvalidArgument, "unknown sort_by %v", req.GetSortBy())
+	}
+
+	switch req.GetExpiry() {
+	case shortenerpb.ExpiryFilter_EXPIRY_FILTER_EXPIRED:
+		expired := true
+		q.Expired = &expired
+	case shortenerpb.ExpiryFilter_EXPIRY_FILTER_UNEXPIRED:
+		expired := false
+		q.Expired = &expired
+	}
+	switch req.GetActive() {
+	case shortenerpb.ActiveFilter_ACTIVE_FILTER_ACTIVE:
+		active := true
+		q.Active = &active
+	case shortenerpb.ActiveFilter_ACTIVE_FILTER_DISABLED:
+		active := false
+		q.Active = &active
+	}
+
+	var err error
+	if req.GetCreatedFrom() != "" {
+		if q.CreatedFrom, err = time.Parse(time.RFC3339, req.GetCreatedFrom()); err != nil {
+			return q, status.Errorf(codes.InvalidArgument, "invalid created_from: %v", err)
+		}
+	}
+	if req.GetCreatedTo() != "" {
+		if q.CreatedTo, err = time.Parse(time.RFC3339, req.GetCreatedTo()); err != nil {
+			return q, status.Errorf(codes.InvalidArgument, "invalid created_to: %v", err)
+		}
+	}
+
+	if req.GetPageToken() != "" {
+		t, err := decodePageToken(req.GetPageToken())
+		if err != nil {
+			return q, status.Errorf(codes.InvalidArgument, "invalid page_token")
+		}
+		if t.SortBy != q.SortBy || t.Ascending != q.Ascending {
+			return q, status.Errorf(codes.InvalidArgument, "page_token was issued for a different sort order")
+		}
+		q.After = &repository.URLCursor{Time: t.Time, Count: t.Count, ID: t.ID}
+	}
+
+	return q, nil
+}
+
+// ListURLs returns a page of the caller's URLs. The next page starts after
+// the last URL of this one, so links created or deleted in between neither
+// repeat nor get skipped.
+func (s *server) ListURLs(ctx context.Context, req *shortenerpb.ListURLsRequest) (*shortenerpb.ListURLsResponse, error) {
+	log.Printf("Received ListURLs request: %v\n", req.GetUserId())
+
+	q, err := listQuery(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fetch one extra URL to learn whether there is another page
+	pageSize := q.Limit
+	q.Limit++
+	urls, err := s.urls.ListByUser(ctx, q)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "database error: %v", err)
+	}
+
+	res := &shortenerpb.ListURLsResponse{}
+	if len(urls) > pageSize {
+		urls = urls[:pageSize]
+		last := repository.CursorOf(&urls[pageSize-1], q.SortBy)
+		res.NextPageToken = encodePageToken(pageToken{
+			SortBy:    q.SortBy,
+			Ascending: q.Ascending,
+			Time:      last.Time,
+			Count:     last.Count,
+			ID:        last.ID,
+		})
+	}
+	for i := range urls {
+		res.Urls = append(res.Urls, s.urlInfo(&urls[i]))
+	}
+	return res, nil
+}
@@ -2,158 +2,248 @@ package main
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"log"
 	"net"
+	"os/signal"
+	"strings"
+	"syscall"
 	"time"
 
-	"github.com/segmentio/kafka-go"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
+	"github.com/Farhang-Osman/url-shortener-project/common/config"
 	db "github.com/Farhang-Osman/url-shortener-project/common/db"
+	"github.com/Farhang-Osman/url-shortener-project/common/eventbus"
+	"github.com/Farhang-Osman/url-shortener-project/common/outbox"
+	"github.com/Farhang-Osman/url-shortener-project/common/repository"
 	shortenerpb "github.com/Farhang-Osman/url-shortener-project/pkg/proto/shortenerpb"
 )
 
-const (
-	kafkaBroker  = "localhost:9092"
-	createdTopic = "url-created-events"
-)
-
 type server struct {
 	shortenerpb.UnimplementedShortenerServiceServer
-	kafkaWriter *kafka.Writer
+	urls    repository.URLRepository
+	relay   *outbox.Relay
+	codes   CodeGenerator
+	aliases *aliasValidator
+	dests   *destinationValidator
+	baseURL string
 }
 
-type URLCreatedEvent struct {
-	ShortCode string    `json:"short_code"`
-	LongURL   string    `json:"long_url"`
-	UserID    string    `json:"user_id"`
-	CreatedAt time.Time `json:"created_at"`
+// newServer returns a server storing URLs in urls, whose events are relayed
+// from queue to publisher. Messages are keyed by short code so each link's
+// events stay in order. Links without a custom alias get a code from codes,
+// custom aliases are checked by aliases, destinations by dests and short
+// URLs are built under baseURL.
+func newServer(urls repository.URLRepository, queue repository.OutboxRepository, publisher eventbus.Publisher,
+	codes CodeGenerator, aliases *aliasValidator, dests *destinationValidator, baseURL string) *server {
+	return &server{
+		urls:    urls,
+		relay:   outbox.NewRelay(queue, publisher),
+		codes:   codes,
+		aliases: aliases,
+		dests:   dests,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+	}
 }
 
-func newServer() *server {
-	// Initialize Kafka writer
-	writer := &kafka.Writer{
-		Addr:     kafka.TCP(kafkaBroker),
-		Topic:    createdTopic,
-		Balancer: &kafka.LeastBytes{},
+// newURL validates a ShortenURL request and builds the URL to store, short
+// of its short code
+func (s *server) newURL(req *shortenerpb.ShortenURLRequest) (*repository.URL, error) {
+	longURL, err := s.dests.Normalize(req.GetLongUrl())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid long_url: %v", err)
 	}
 
-	return &server{
-		kafkaWriter: writer,
+	if req.GetCustomAlias() != "" {
+		if err := s.aliases.Validate(req.GetCustomAlias()); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid custom_alias: %v", err)
+		}
 	}
+
+	// Parse expires_at if provided
+	expiresAt, err := parseExpiresAt(req.GetExpiresAt())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid expires_at format: %v", err)
+	}
+
+	tags, err := normalizeTags(req.GetTags())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid tags: %v", err)
+	}
+
+	return &repository.URL{
+		LongURL:   longURL,
+		UserID:    req.GetUserId(),
+		ExpiresAt: expiresAt,
+		CreatedAt: time.Now(),
+		Tags:      tags,
+	}, nil
 }
 
 func (s *server) ShortenURL(ctx context.Context, req *shortenerpb.ShortenURLRequest) (*shortenerpb.ShortenURLResponse, error) {
 	log.Printf("Received ShortenURL request: %v\n", req.GetLongUrl())
 
-	// Generate short code (use custom alias if provided)
-	var shortCode string
-	if req.GetCustomAlias() != "" {
-		shortCode = req.GetCustomAlias()
+	u, err := s.newURL(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Store the URL; its created event is committed with it and published
+	// by the outbox relay afterwards. The insert itself claims the short
+	// code, so of two requests for the same alias only one can succeed.
+	alias := req.GetCustomAlias()
+	for attempt := 1; ; attempt++ {
+		u.ShortCode = alias
+		if alias == "" {
+			if u.ShortCode, err = s.codes.Next(ctx); err != nil {
+				return nil, status.Errorf(codes.Internal, "failed to generate short code: %v", err)
+			}
+		}
 
-		// Check if custom alias already exists
-		var exists bool
-		err := db.DB.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM urls WHERE short_code = $1)", shortCode).Scan(&exists)
-		if err != nil {
-			return nil, status.Errorf(codes.Internal, "database error: %v", err)
+		err = s.urls.Create(ctx, u, outbox.URLCreated(u.ShortCode, u.LongURL, u.UserID, u.CreatedAt))
+		if err == nil {
+			break
 		}
-		if exists {
+		if !errors.Is(err, repository.ErrConflict) {
+			return nil, status.Errorf(codes.Internal, "failed to store URL: %v", err)
+		}
+		if alias != "" {
 			return nil, status.Errorf(codes.AlreadyExists, "custom alias already exists")
 		}
-	} else {
-		// Generate random short code and ensure it's unique
-		for {
-			shortCode = generateShortCode()
-			var exists bool
-			err := db.DB.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM urls WHERE short_code = $1)", shortCode).Scan(&exists)
-			if err != nil {
-				return nil, status.Errorf(codes.Internal, "database error: %v", err)
-			}
-			if !exists {
-				break
-			}
+		// A generated code collided with a custom alias; try the next one
+		if attempt == maxCodeAttempts {
+			return nil, status.Errorf(codes.Internal, "failed to generate a unique short code")
 		}
 	}
 
-	// Parse expires_at if provided
-	var expiresAt *time.Time
-	if req.GetExpiresAt() != "" {
-		parsedTime, err := parseExpiresAt(req.GetExpiresAt())
-		if err != nil {
-			return nil, status.Errorf(codes.InvalidArgument, "invalid expires_at format: %v", err)
-		}
-		expiresAt = parsedTime
-	}
+	log.Printf("URL shortened successfully: %s -> %s", u.LongURL, u.ShortCode)
 
-	// Insert URL into database
-	var userID *string
-	if req.GetUserId() != "" {
-		userID = &req.UserId
-	}
+	return &shortenerpb.ShortenURLResponse{
+		ShortCode: u.ShortCode,
+	}, nil
+}
 
-	createdAt := time.Now()
-	_, err := db.DB.Exec(ctx,
-		"INSERT INTO urls (short_code, long_url, user_id, expires_at, created_at) VALUES ($1, $2, $3, $4, $5)",
-		shortCode, req.GetLongUrl(), userID, expiresAt, createdAt)
+func (s *server) GetOriginalURL(ctx context.Context, req *shortenerpb.GetOriginalURLRequest) (*shortenerpb.GetOriginalURLResponse, error) {
+	log.Printf("Received GetOriginalURL request: %v\n", req.GetShortCode())
 
+	u, err := s.urls.Get(ctx, req.GetShortCode())
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to store URL: %v", err)
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, status.Errorf(codes.NotFound, "short URL not found")
+		}
+		return nil, status.Errorf(codes.Internal, "database error: %v", err)
 	}
 
-	// Publish event to Kafka
-	event := URLCreatedEvent{
-		ShortCode: shortCode,
-		LongURL:   req.GetLongUrl(),
-		UserID:    req.GetUserId(),
-		CreatedAt: createdAt,
+	if !u.Active {
+		return &shortenerpb.GetOriginalURLResponse{Disabled: true}, nil
 	}
 
-	eventBytes, err := json.Marshal(event)
-	if err != nil {
-		log.Printf("Warning: failed to marshal URL created event: %v", err)
-	} else {
-		err = s.kafkaWriter.WriteMessages(ctx, kafka.Message{
-			Value: eventBytes,
-		})
-		if err != nil {
-			log.Printf("Warning: failed to publish URL created event to Kafka: %v", err)
-		} else {
-			log.Printf("Published URL created event to Kafka for short code: %s", shortCode)
-		}
+	if u.ExpiresAt != nil && time.Now().After(*u.ExpiresAt) {
+		return nil, status.Errorf(codes.FailedPrecondition, "short URL has expired")
 	}
 
-	log.Printf("URL shortened successfully: %s -> %s", req.GetLongUrl(), shortCode)
-
-	return &shortenerpb.ShortenURLResponse{
-		ShortCode: shortCode,
+	return &shortenerpb.GetOriginalURLResponse{
+		LongUrl:   u.LongURL,
+		ExpiresAt: formatOptionalTime(u.ExpiresAt),
 	}, nil
 }
 
-// ... (keep the rest of your existing GetOriginalURL and UpdateURLDestination functions unchanged)
-
 func main() {
+	var cfg Config
+	args := config.MustLoad(&cfg, "shortener-service")
+
 	// Initialize database connection pool
-	if err := db.InitDB(); err != nil {
+	if err := db.InitDB(cfg.Database.URL); err != nil {
 		log.Fatalf("failed to initialize database: %v", err)
 	}
 	defer db.CloseDB()
 
-	srv := newServer()
-	defer srv.kafkaWriter.Close()
+	// Subcommands:
+	//   shortener-service [flags] migrate up|down|status
+	if len(args) > 0 {
+		switch args[0] {
+		case "migrate":
+			if err := db.RunMigrateCommand(args[1:]); err != nil {
+				log.Fatalf("failed to migrate database: %v", err)
+			}
+			return
+		default:
+			log.Fatalf("unknown command %q", args[0])
+		}
+	}
+
+	if cfg.Database.MigrateOnStartup {
+		if err := db.MigrateUp(context.Background()); err != nil {
+			log.Fatalf("failed to apply migrations: %v", err)
+		}
+	}
+
+	publisher := eventbus.NewKafkaPublisher(cfg.Kafka.Brokers...)
+	defer publisher.Close()
+
+	aliases, err := newAliasValidator(cfg.Aliases)
+	if err != nil {
+		log.Fatalf("failed to load alias rules: %v", err)
+	}
+
+	dests, err := newDestinationValidator(cfg.Destinations, cfg.PublicBaseURL)
+	if err != nil {
+		log.Fatalf("failed to set up destination checks: %v", err)
+	}
+
+	// Create the invalidation topics before the relay publishes to them, so
+	// the broker doesn't create them with its default partition count
+	topicCtx, cancelTopics := context.WithTimeout(context.Background(), 30*time.Second)
+	err = eventbus.EnsureSingleTopicPartition(topicCtx, cfg.Kafka.Brokers, outbox.TopicURLUpdated, outbox.TopicURLDeleted)
+	cancelTopics()
+	if err != nil {
+		log.Fatalf("invalidation topics are unusable: %v", err)
+	}
 
-	lis, err := net.Listen("tcp", ":50052")
+	urls := repository.NewPostgresURLRepository(db.DB)
+	srv := newServer(urls, urls, publisher, newCodeGenerator(cfg.Codes, urls), aliases, dests, cfg.PublicBaseURL)
+
+	lis, err := net.Listen("tcp", cfg.ListenAddr)
 	if err != nil {
 		log.Fatalf("failed to listen: %v", err)
 	}
 
+	// click_count and last_accessed are fed from the click events rather
+	// than from lookups, which redirect-service mostly serves from its cache
+	clickSub := eventbus.NewKafkaSubscriber(eventbus.KafkaSubscriberConfig{
+		Brokers: cfg.Kafka.Brokers,
+		Topic:   clickTopic,
+		GroupID: "shortener-click-group",
+		MaxWait: 1 * time.Second,
+	})
+	clicks := newClickRecorder(urls, clickSub)
+
 	s := grpc.NewServer()
 	shortenerpb.RegisterShortenerServiceServer(s, srv)
 
-	log.Printf("Shortener Service listening at %v", lis.Addr())
-	if err := s.Serve(lis); err != nil {
-		log.Fatalf("failed to serve: %v", err)
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	go func() {
+		log.Printf("Shortener Service listening at %v", lis.Addr())
+		if err := s.Serve(lis); err != nil {
+			log.Fatalf("failed to serve: %v", err)
+		}
+	}()
+
+	<-ctx.Done()
+	log.Println("Shutting down shortener service...")
+	s.GracefulStop()
+
+	// Flush the click tallies fetched so far and leave the consumer group,
+	// then let the relay stop; what it hasn't published stays in the outbox
+	clicks.Close()
+	if err := clickSub.Close(); err != nil {
+		log.Printf("Error leaving consumer group: %v", err)
 	}
+	srv.relay.Close()
+	log.Println("Shortener service stopped")
 }
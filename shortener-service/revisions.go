@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Farhang-Osman/url-shortener-project/common/outbox"
+	"github.com/Farhang-Osman/url-shortener-project/common/repository"
+	shortenerpb "github.com/Farhang-Osman/url-shortener-project/pkg/proto/shortenerpb"
+)
+
+// checkOwner returns PermissionDenied unless userID owns u
+func checkOwner(u *repository.URL, userID string) error {
+	if userID == "" || u.UserID != userID {
+		return status.Errorf(codes.PermissionDenied, "you do not own this short URL")
+	}
+	return nil
+}
+
+// ownedURL looks up a short code and verifies that userID owns it
+func (s *server) ownedURL(ctx context.Context, shortCode, userID string) (*repository.URL, error) {
+	u, err := s.urls.Get(ctx, shortCode)
+	if err != nil {
+		return nil, urlError(err)
+	}
+	if err := checkOwner(u, userID); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// urlError maps a repository error to a gRPC status. Status errors returned
+// by callbacks pass through unchanged.
+func urlError(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	if errors.Is(err, repository.ErrNotFound) {
+		return status.Errorf(codes.NotFound, "short URL not found")
+	}
+	return status.Errorf(codes.Internal, "database error: %v", err)
+}
+
+// setDestination points an owned URL at a new destination, records the change
+// in its revisions and queues a url-updated event for cache invalidation
+func (s *server) setDestination(ctx context.Context, shortCode, userID, newURL string) error {
+	_, err := s.urls.UpdateDestination(ctx, shortCode, userID,
+		func(u *repository.URL) (string, error) {
+			if err := checkOwner(u, userID); err != nil {
+				return "", err
+			}
+			return newURL, nil
+		},
+		func(u *repository.URL) repository.Event {
+			return outbox.URLUpdated(u, userID)
+		})
+	if err != nil {
+		return urlError(err)
+	}
+	return nil
+}
+
+func (s *server) UpdateURLDestination(ctx context.Context, req *shortenerpb.UpdateURLDestinationRequest) (*shortenerpb.UpdateURLDestinationResponse, error) {
+	log.Printf("Received UpdateURLDestination request: %v -> %v\n", req.GetShortCode(), req.GetNewLongUrl())
+
+	newURL, err := s.dests.Normalize(req.GetNewLongUrl())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid new_long_url: %v", err)
+	}
+
+	if err := s.setDestination(ctx, req.GetShortCode(), req.GetUserId(), newURL); err != nil {
+		return nil, err
+	}
+
+	log.Printf("URL destination updated: %s -> %s", req.GetShortCode(), newURL)
+
+	return &shortenerpb.UpdateURLDestinationResponse{
+		ShortCode: req.GetShortCode(),
+		Message:   "URL destination updated successfully",
+	}, nil
+}
+
+func (s *server) ListURLRevisions(ctx context.Context, req *shortenerpb.ListURLRevisionsRequest) (*shortenerpb.ListURLRevisionsResponse, error) {
+	log.Printf("Received ListURLRevisions request: %v\n", req.GetShortCode())
+
+	u, err := s.ownedURL(ctx, req.GetShortCode(), req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+
+	stored, err := s.urls.Revisions(ctx, u.ID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "database error: %v", err)
+	}
+
+	revisions := make([]*shortenerpb.URLRevision, 0, len(stored))
+	for _, rev := range stored {
+		revisions = append(revisions, &shortenerpb.URLRevision{
+			RevisionId:      rev.ID,
+			PreviousLongUrl: rev.PreviousLongURL,
+			NewLongUrl:      rev.NewLongURL,
+			ChangedBy:       rev.ChangedBy,
+			ChangedAt:       rev.ChangedAt.Format(time.RFC3339),
+		})
+	}
+
+	return &shortenerpb.ListURLRevisionsResponse{Revisions: revisions}, nil
+}
+
+func (s *server) RestoreURLRevision(ctx context.Context, req *shortenerpb.RestoreURLRevisionRequest) (*shortenerpb.UpdateURLDestinationResponse, error) {
+	log.Printf("Received RestoreURLRevision request: %v @ %v\n", req.GetShortCode(), req.GetRevisionId())
+
+	u, err := s.ownedURL(ctx, req.GetShortCode(), req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+
+	rev, err := s.urls.Revision(ctx, u.ID, req.GetRevisionId())
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, status.Errorf(codes.NotFound, "revision not found")
+		}
+		return nil, status.Errorf(codes.Internal, "database error: %v", err)
+	}
+	// Destinations stored before validation was added may not pass it
+	restoredURL, err := s.dests.Normalize(rev.PreviousLongURL)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "revision destination is no longer allowed: %v", err)
+	}
+
+	if err := s.setDestination(ctx, req.GetShortCode(), req.GetUserId(), restoredURL); err != nil {
+		return nil, err
+	}
+
+	log.Printf("URL destination restored: %s -> %s", req.GetShortCode(), restoredURL)
+
+	return &shortenerpb.UpdateURLDestinationResponse{
+		ShortCode: req.GetShortCode(),
+		Message:   "URL destination restored successfully",
+	}, nil
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Farhang-Osman/url-shortener-project/common/eventbus"
+	"github.com/Farhang-Osman/url-shortener-project/common/repository"
+	shortenerpb "github.com/Farhang-Osman/url-shortener-project/pkg/proto/shortenerpb"
+)
+
+const testUserID = "7c9e6679-7425-40de-944b-e07fc1f90ae7"
+
+func newTestServer(t *testing.T, urls *repository.MemoryURLRepository, gen CodeGenerator) *server {
+	t.Helper()
+	aliases, err := newAliasValidator(aliasConfig{MinLength: 3, MaxLength: maxShortCodeLength})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dests, err := newDestinationValidator(destinationConfig{}, "https://sho.rt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := newServer(urls, urls, eventbus.NewMemory().Publisher(), gen, aliases, dests, "https://sho.rt")
+	t.Cleanup(srv.relay.Close)
+	return srv
+}
+
+// shortenInParallel sends n ShortenURL requests at once and returns their
+// short codes and errors by caller
+func shortenInParallel(srv *server, n int, req *shortenerpb.ShortenURLRequest) ([]string, []error) {
+	shortCodes := make([]string, n)
+	errs := make([]error, n)
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			res, err := srv.ShortenURL(context.Background(), req)
+			shortCodes[i], errs[i] = res.GetShortCode(), err
+		}()
+	}
+	close(start)
+	wg.Wait()
+	return shortCodes, errs
+}
+
+// TestShortenURLCustomAliasRace lets many callers claim the same alias at
+// once. Exactly one may get it and everyone else must see AlreadyExists, not
+// an internal error.
+func TestShortenURLCustomAliasRace(t *testing.T) {
+	urls := repository.NewMemoryURLRepository()
+	srv := newTestServer(t, urls, randomGenerator{length: 7})
+
+	const callers = 50
+	shortCodes, errs := shortenInParallel(srv, callers, &shortenerpb.ShortenURLRequest{
+		LongUrl:     "https://example.com/launch",
+		CustomAlias: "launch",
+		UserId:      testUserID,
+	})
+
+	created := 0
+	for i, err := range errs {
+		switch status.Code(err) {
+		case codes.OK:
+			created++
+			if shortCodes[i] != "launch" {
+				t.Errorf("caller %d got short code %q, want launch", i, shortCodes[i])
+			}
+		case codes.AlreadyExists:
+		default:
+			t.Errorf("caller %d: %v", i, err)
+		}
+	}
+	if created != 1 {
+		t.Fatalf("%d callers got the alias, want 1", created)
+	}
+
+	stored, err := urls.ListByUser(context.Background(), repository.URLListQuery{UserID: testUserID, Limit: callers})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stored) != 1 {
+		t.Errorf("%d URLs stored, want 1", len(stored))
+	}
+}
+
+// collidingGenerator hands out every code twice, so half of all inserts by
+// parallel callers hit a code that another caller just took
+type collidingGenerator struct {
+	mu    sync.Mutex
+	calls int
+	codes CodeGenerator
+	last  string
+}
+
+func (g *collidingGenerator) Next(ctx context.Context) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.calls++
+	if g.calls%2 == 1 {
+		code, err := g.codes.Next(ctx)
+		if err != nil {
+			return "", err
+		}
+		g.last = code
+	}
+	return g.last, nil
+}
+
+// TestShortenURLGeneratedCodeRetry checks that a generated code that is
+// already taken is replaced instead of failing the request
+func TestShortenURLGeneratedCodeRetry(t *testing.T) {
+	urls := repository.NewMemoryURLRepository()
+	gen := &collidingGenerator{codes: newSequenceGenerator(urls, 7)}
+	srv := newTestServer(t, urls, gen)
+
+	const callers = 50
+	shortCodes, errs := shortenInParallel(srv, callers, &shortenerpb.ShortenURLRequest{
+		LongUrl: "https://example.com/",
+		UserId:  testUserID,
+	})
+
+	seen := make(map[string]bool)
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("caller %d: %v", i, err)
+		}
+		if seen[shortCodes[i]] {
+			t.Fatalf("short code %q handed out twice", shortCodes[i])
+		}
+		seen[shortCodes[i]] = true
+	}
+	if gen.calls <= callers {
+		t.Errorf("generator called %d times for %d callers, want retries", gen.calls, callers)
+	}
+}
@@ -1,31 +1,11 @@
 package main
 
 import (
-	"crypto/rand"
-	"encoding/base64"
+	"fmt"
 	"strings"
 	"time"
 )
 
-// generateShortCode generates a random short code for URLs
-func generateShortCode() string {
-	// Generate 6 random bytes
-	bytes := make([]byte, 6)
-	rand.Read(bytes)
-
-	// Encode to base64 and clean up
-	encoded := base64.URLEncoding.EncodeToString(bytes)
-	// Remove padding and make it URL-safe
-	encoded = strings.TrimRight(encoded, "=")
-
-	// Take first 8 characters to ensure consistent length
-	if len(encoded) > 8 {
-		encoded = encoded[:8]
-	}
-
-	return encoded
-}
-
 // parseExpiresAt parses ISO 8601 format string to time.Time
 func parseExpiresAt(expiresAtStr string) (*time.Time, error) {
 	if expiresAtStr == "" {
@@ -40,10 +20,36 @@ func parseExpiresAt(expiresAtStr string) (*time.Time, error) {
 	return &t, nil
 }
 
-// formatExpiresAt formats time.Time to ISO 8601 string
-func formatExpiresAt(t *time.Time) string {
+// formatOptionalTime formats an optional time as an ISO 8601 string, empty if nil
+func formatOptionalTime(t *time.Time) string {
 	if t == nil {
 		return ""
 	}
 	return t.Format(time.RFC3339)
 }
+
+const (
+	maxTags      = 20
+	maxTagLength = 64
+)
+
+// normalizeTags trims tags and drops empty and repeated ones
+func normalizeTags(tags []string) ([]string, error) {
+	var normalized []string
+	seen := make(map[string]bool)
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		if len(tag) > maxTagLength {
+			return nil, fmt.Errorf("tag %q is longer than %d bytes", tag, maxTagLength)
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	if len(normalized) > maxTags {
+		return nil, fmt.Errorf("at most %d tags are allowed", maxTags)
+	}
+	return normalized, nil
+}
@@ -0,0 +1,11 @@
+package main
+
+import "github.com/Farhang-Osman/url-shortener-project/common/config"
+
+// Config is loaded from the environment, flags and an optional YAML file;
+// see package config
+type Config struct {
+	ListenAddr string          `yaml:"listen_addr" env:"LISTEN_ADDR" flag:"listen" default:":50051" usage:"gRPC listen address"`
+	Database   config.Database `yaml:"database"`
+	JWT        config.JWT      `yaml:"jwt"`
+}
@@ -2,27 +2,33 @@ package main
 
 import (
 	"context"
+	"errors"
 	"log"
 	"net"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
-	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgconn"
 	"golang.org/x/crypto/bcrypt"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
+	"github.com/Farhang-Osman/url-shortener-project/common/config"
 	db "github.com/Farhang-Osman/url-shortener-project/common/db" // Import the common db package
+	"github.com/Farhang-Osman/url-shortener-project/common/repository"
 	userpb "github.com/Farhang-Osman/url-shortener-project/pkg/proto/userpb"
 )
 
-// Replace with your actual GitHub username
-const jwtSecret = "your-secret-key-change-this-in-production"
-
 type server struct {
 	userpb.UnimplementedUserServiceServer
+	users     repository.UserRepository
+	jwtSecret []byte
+}
+
+// newServer returns a server storing users in users and signing their
+// tokens with jwtSecret
+func newServer(users repository.UserRepository, jwtSecret string) *server {
+	return &server{users: users, jwtSecret: []byte(jwtSecret)}
 }
 
 func (s *server) RegisterUser(ctx context.Context, req *userpb.RegisterUserRequest) (*userpb.RegisterUserResponse, error) {
@@ -35,22 +41,20 @@ func (s *server) RegisterUser(ctx context.Context, req *userpb.RegisterUserReque
 	}
 
 	// Insert user into database
-	var userID string
-	// Use db.DB from the common package
-	err = db.DB.QueryRow(ctx,
-		"INSERT INTO users (username, email, password_hash) VALUES ($1, $2, $3) RETURNING id",
-		req.GetUsername(), req.GetEmail(), hashedPassword).Scan(&userID)
-
-	if err != nil {
-		// Check for unique constraint violation
-		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" { // 23505 is unique_violation
+	user := &repository.User{
+		Username:     req.GetUsername(),
+		Email:        req.GetEmail(),
+		PasswordHash: hashedPassword,
+	}
+	if err := s.users.Create(ctx, user); err != nil {
+		if errors.Is(err, repository.ErrConflict) {
 			return nil, status.Errorf(codes.AlreadyExists, "username or email already exists")
 		}
 		return nil, status.Errorf(codes.Internal, "failed to create user: %v", err)
 	}
 
 	return &userpb.RegisterUserResponse{
-		UserId:  userID,
+		UserId:  user.ID,
 		Message: "User registered successfully",
 	}, nil
 }
@@ -59,22 +63,17 @@ func (s *server) LoginUser(ctx context.Context, req *userpb.LoginUserRequest) (*
 	log.Printf("Received LoginUser request: %v\n", req.GetUsername())
 
 	// Get user from database
-	var userID string
-	var hashedPassword []byte // Change to []byte for bcrypt.CompareHashAndPassword
-	// Use db.DB from the common package
-	err := db.DB.QueryRow(ctx,
-		"SELECT id, password_hash FROM users WHERE username = $1",
-		req.GetUsername()).Scan(&userID, &hashedPassword)
-
+	user, err := s.users.GetByUsername(ctx, req.GetUsername())
 	if err != nil {
-		if err == pgx.ErrNoRows {
+		if errors.Is(err, repository.ErrNotFound) {
 			return nil, status.Errorf(codes.NotFound, "user not found")
 		}
 		return nil, status.Errorf(codes.Internal, "database error: %v", err)
 	}
+	userID := user.ID
 
 	// Verify password
-	err = bcrypt.CompareHashAndPassword(hashedPassword, []byte(req.GetPassword()))
+	err = bcrypt.CompareHashAndPassword(user.PasswordHash, []byte(req.GetPassword()))
 	if err != nil {
 		return nil, status.Errorf(codes.Unauthenticated, "invalid password")
 	}
@@ -85,7 +84,7 @@ func (s *server) LoginUser(ctx context.Context, req *userpb.LoginUserRequest) (*
 		"exp":     time.Now().Add(time.Hour * 24).Unix(), // 24 hours
 	})
 
-	tokenString, err := token.SignedString([]byte(jwtSecret))
+	tokenString, err := token.SignedString(s.jwtSecret)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to generate token: %v", err)
 	}
@@ -105,7 +104,7 @@ func (s *server) ValidateToken(ctx context.Context, req *userpb.ValidateTokenReq
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, status.Errorf(codes.Unauthenticated, "unexpected signing method")
 		}
-		return []byte(jwtSecret), nil
+		return s.jwtSecret, nil
 	})
 
 	if err != nil || !token.Valid {
@@ -138,19 +137,42 @@ func (s *server) ValidateToken(ctx context.Context, req *userpb.ValidateTokenReq
 }
 
 func main() {
+	var cfg Config
+	args := config.MustLoad(&cfg, "user-service")
+
 	// Initialize database connection pool
-	if err := db.InitDB(); err != nil {
+	if err := db.InitDB(cfg.Database.URL); err != nil {
 		log.Fatalf("failed to initialize database: %v", err)
 	}
 	defer db.CloseDB()
 
-	lis, err := net.Listen("tcp", ":50051")
+	// Subcommands:
+	//   user-service [flags] migrate up|down|status
+	if len(args) > 0 {
+		switch args[0] {
+		case "migrate":
+			if err := db.RunMigrateCommand(args[1:]); err != nil {
+				log.Fatalf("failed to migrate database: %v", err)
+			}
+			return
+		default:
+			log.Fatalf("unknown command %q", args[0])
+		}
+	}
+
+	if cfg.Database.MigrateOnStartup {
+		if err := db.MigrateUp(context.Background()); err != nil {
+			log.Fatalf("failed to apply migrations: %v", err)
+		}
+	}
+
+	lis, err := net.Listen("tcp", cfg.ListenAddr)
 	if err != nil {
 		log.Fatalf("failed to listen: %v", err)
 	}
 
 	s := grpc.NewServer()
-	userpb.RegisterUserServiceServer(s, &server{})
+	userpb.RegisterUserServiceServer(s, newServer(repository.NewPostgresUserRepository(db.DB), cfg.JWT.Secret))
 
 	log.Printf("User Service listening at %v", lis.Addr())
 	if err := s.Serve(lis); err != nil {